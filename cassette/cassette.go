@@ -0,0 +1,363 @@
+// Package cassette records HTTP traffic to JSONL files and replays it, so the
+// server can run against recorded Amadeus responses without network access
+// or quota.
+//
+// Each line of a cassette is one Interaction. Credentials are stripped before
+// anything is written: the Authorization header, the client_id and
+// client_secret form fields and the access_token of token responses.
+// Replayed requests are matched by method, path, query and body after the
+// same stripping, so a recorded token request matches any credentials.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// stripped replaces every credential in a cassette.
+const stripped = "[STRIPPED]"
+
+// ErrNoInteraction is returned by Player for requests that were not
+// recorded.
+var ErrNoInteraction = errors.New("cassette: no recorded interaction")
+
+var (
+	strippedHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "Proxy-Authorization"}
+	strippedForm    = []string{"client_id", "client_secret"}
+	strippedJSON    = []string{"access_token", "refresh_token"}
+)
+
+// missError is returned for a request that was not recorded. Retrying it
+// cannot help.
+type missError struct {
+	method, uri string
+}
+
+func (e missError) Error() string {
+	return fmt.Sprintf("%v for %s %s", ErrNoInteraction, e.method, e.uri)
+}
+
+func (e missError) Is(target error) bool { return target == ErrNoInteraction }
+func (e missError) Permanent() bool      { return true }
+
+// Interaction is a request and the response it got.
+type Interaction struct {
+	RecordedAt time.Time `json:"recordedAt"`
+	Request    Request   `json:"request"`
+	Response   Response  `json:"response"`
+}
+
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   Body        `json:"body,omitempty"`
+}
+
+type Response struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   Body        `json:"body,omitempty"`
+}
+
+// Body keeps JSON bodies readable in the cassette and any other body as
+// text.
+type Body struct {
+	JSON json.RawMessage `json:"json,omitempty"`
+	Text string          `json:"text,omitempty"`
+}
+
+func newBody(raw []byte) Body {
+	if len(raw) == 0 {
+		return Body{}
+	}
+	if json.Valid(raw) {
+		var compact bytes.Buffer
+		json.Compact(&compact, raw)
+		return Body{JSON: compact.Bytes()}
+	}
+	return Body{Text: string(raw)}
+}
+
+// Bytes returns the body as sent.
+func (b Body) Bytes() []byte {
+	if b.JSON != nil {
+		return b.JSON
+	}
+	return []byte(b.Text)
+}
+
+// key identifies the requests an interaction can answer.
+func (r Request) key() string {
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return r.Method + " " + r.URL + " " + string(r.Body.Bytes())
+	}
+	return r.Method + " " + u.RequestURI() + " " + string(r.Body.Bytes())
+}
+
+// newRequest copies req with its credentials stripped. It consumes the body
+// and puts back a copy.
+func newRequest(req *http.Request) (Request, error) {
+	var raw []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		if raw, err = io.ReadAll(req.Body); err != nil {
+			return Request{}, err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(raw))
+	}
+	if strings.HasPrefix(req.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		if form, err := url.ParseQuery(string(raw)); err == nil {
+			for _, field := range strippedForm {
+				if form.Has(field) {
+					form.Set(field, stripped)
+				}
+			}
+			raw = []byte(form.Encode())
+		}
+	}
+	return Request{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Header: stripHeader(req.Header),
+		Body:   stripJSON(newBody(raw)),
+	}, nil
+}
+
+func stripHeader(h http.Header) http.Header {
+	h = h.Clone()
+	for _, name := range strippedHeaders {
+		h.Del(name)
+	}
+	if len(h) == 0 {
+		return nil
+	}
+	return h
+}
+
+// stripJSON replaces the tokens in a top-level JSON object.
+func stripJSON(b Body) Body {
+	if b.JSON == nil {
+		return b
+	}
+	var object map[string]json.RawMessage
+	if json.Unmarshal(b.JSON, &object) != nil {
+		return b
+	}
+	changed := false
+	for _, field := range strippedJSON {
+		if _, ok := object[field]; ok {
+			object[field] = json.RawMessage(`"` + stripped + `"`)
+			changed = true
+		}
+	}
+	if !changed {
+		return b
+	}
+	raw, err := json.Marshal(object)
+	if err != nil {
+		return b
+	}
+	return Body{JSON: raw}
+}
+
+// Recorder is a RoundTripper that writes every interaction through Base to
+// the cassette at Path, appending to it. Cassettes hold traveler data, so the
+// file is readable only by its owner.
+type Recorder struct {
+	Base http.RoundTripper
+	Path string
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := r.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	recorded, err := newRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		// Failures without a response cannot be replayed.
+		return nil, err
+	}
+	raw, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(raw))
+
+	interaction := Interaction{
+		RecordedAt: time.Now().UTC(),
+		Request:    recorded,
+		Response: Response{
+			Status: resp.StatusCode,
+			Header: stripHeader(resp.Header),
+			Body:   stripJSON(newBody(raw)),
+		},
+	}
+	if err := r.write(interaction); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (r *Recorder) write(interaction Interaction) error {
+	line, err := json.Marshal(interaction)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		if err := os.MkdirAll(filepath.Dir(r.Path), 0o755); err != nil {
+			return err
+		}
+		if r.file, err = os.OpenFile(r.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600); err != nil {
+			return fmt.Errorf("cassette: %w", err)
+		}
+		// The mode above only applies to new files.
+		if err := r.file.Chmod(0o600); err != nil {
+			r.file.Close()
+			r.file = nil
+			return fmt.Errorf("cassette: %w", err)
+		}
+	}
+	_, err = r.file.Write(append(line, '\n'))
+	return err
+}
+
+// Close closes the cassette file.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+// Player is a RoundTripper that answers from recorded interactions without
+// any network access. Interactions matching the same request are replayed in
+// recording order, the last one repeating once the others are used, so
+// retries and polling see the same sequence as when they were recorded.
+type Player struct {
+	mu    sync.Mutex
+	queue map[string][]Interaction
+}
+
+// Load reads the cassettes at paths. A directory stands for every .jsonl
+// file in it.
+func Load(paths ...string) (*Player, error) {
+	p := &Player{queue: map[string][]Interaction{}}
+	for _, path := range paths {
+		files := []string{path}
+		if info, err := os.Stat(path); err != nil {
+			return nil, fmt.Errorf("cassette: %w", err)
+		} else if info.IsDir() {
+			if files, err = filepath.Glob(filepath.Join(path, "*.jsonl")); err != nil {
+				return nil, err
+			}
+		}
+		for _, file := range files {
+			if err := p.load(file); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return p, nil
+}
+
+func (p *Player) load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("cassette: %w", err)
+	}
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var interaction Interaction
+		if err := json.Unmarshal(line, &interaction); err != nil {
+			return fmt.Errorf("cassette: %s:%d: %w", path, i+1, err)
+		}
+		p.Add(interaction)
+	}
+	return nil
+}
+
+// Add queues an interaction after those already loaded.
+func (p *Player) Add(interaction Interaction) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	key := interaction.Request.key()
+	p.queue[key] = append(p.queue[key], interaction)
+}
+
+// Len returns the number of interactions left to replay.
+func (p *Player) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n := 0
+	for _, queue := range p.queue {
+		n += len(queue)
+	}
+	return n
+}
+
+func (p *Player) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, err := newRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	queue := p.queue[recorded.key()]
+	var interaction Interaction
+	if len(queue) > 0 {
+		interaction = queue[0]
+		if len(queue) > 1 {
+			p.queue[recorded.key()] = queue[1:]
+		}
+	}
+	p.mu.Unlock()
+	if len(queue) == 0 {
+		return nil, missError{req.Method, req.URL.RequestURI()}
+	}
+
+	body := interaction.Response.Body.Bytes()
+	header := interaction.Response.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Response.Status, http.StatusText(interaction.Response.Status)),
+		StatusCode:    interaction.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
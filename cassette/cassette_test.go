@@ -0,0 +1,140 @@
+package cassette
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	clientID     = "live-client-id"
+	clientSecret = "live-client-secret"
+	accessToken  = "live-access-token"
+)
+
+// amadeus answers a token request and a search, like the real API.
+func amadeus(t *testing.T) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/security/oauth2/token":
+			if r.PostFormValue("client_secret") != clientSecret {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			io.WriteString(w, `{"type":"amadeusOAuth2Token","access_token":"`+accessToken+`","expires_in":1799}`)
+		case "/v2/shopping/flight-offers":
+			if r.Header.Get("Authorization") != "Bearer "+accessToken {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			io.WriteString(w, `{"data":[{"id":"1"}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func tokenRequest(t *testing.T, base, id, secret string) *http.Request {
+	body := "grant_type=client_credentials&client_id=" + id + "&client_secret=" + secret
+	req, err := http.NewRequest("POST", base+"/v1/security/oauth2/token", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+func searchRequest(t *testing.T, base, token string) *http.Request {
+	req, err := http.NewRequest("GET", base+"/v2/shopping/flight-offers?originLocationCode=SCL&max=5", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
+
+func roundTrip(t *testing.T, rt http.RoundTripper, req *http.Request) string {
+	t.Helper()
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", req.Method, req.URL.Path, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("%s %s: status %d", req.Method, req.URL.Path, resp.StatusCode)
+	}
+	return string(body)
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	srv := amadeus(t)
+	path := filepath.Join(t.TempDir(), "cassettes", "search.jsonl")
+	recorder := &Recorder{Path: path}
+
+	if body := roundTrip(t, recorder, tokenRequest(t, srv.URL, clientID, clientSecret)); !strings.Contains(body, accessToken) {
+		t.Errorf("recording changed the live token response: %s", body)
+	}
+	roundTrip(t, recorder, searchRequest(t, srv.URL, accessToken))
+	if err := recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0o600 {
+		t.Errorf("cassette mode = %v, want 0600", mode)
+	}
+	data, _ := os.ReadFile(path)
+	for _, secret := range []string{clientID, clientSecret, accessToken} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("cassette contains %q:\n%s", secret, data)
+		}
+	}
+
+	player, err := Load(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if player.Len() != 2 {
+		t.Fatalf("loaded %d interactions, want 2", player.Len())
+	}
+	// Replays match whatever credentials the server is configured with.
+	body := roundTrip(t, player, tokenRequest(t, srv.URL, "other-id", "other-secret"))
+	if !strings.Contains(body, stripped) || strings.Contains(body, accessToken) {
+		t.Errorf("replayed token response = %s", body)
+	}
+	if body := roundTrip(t, player, searchRequest(t, srv.URL, "replay-token")); body != `{"data":[{"id":"1"}]}` {
+		t.Errorf("replayed search = %s", body)
+	}
+
+	other := searchRequest(t, srv.URL, "replay-token")
+	other.URL.RawQuery = "originLocationCode=LIM&max=5"
+	if _, err := player.RoundTrip(other); !errors.Is(err, ErrNoInteraction) {
+		t.Errorf("unrecorded request: err = %v, want ErrNoInteraction", err)
+	}
+}
+
+func TestRecorderTightensExistingCassette(t *testing.T) {
+	srv := amadeus(t)
+	path := filepath.Join(t.TempDir(), "old.jsonl")
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	recorder := &Recorder{Path: path}
+	roundTrip(t, recorder, searchRequest(t, srv.URL, accessToken))
+	recorder.Close()
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o600 {
+		t.Errorf("cassette mode = %v, want 0600", info.Mode().Perm())
+	}
+}
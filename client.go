@@ -1,23 +1,51 @@
+//go:build ignore
+
+// The command-line client. It is a separate program from the server and is
+// run on its own with "go run client.go".
+
 package main
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
+	"mime"
 	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 
 	"github.com/olekukonko/tablewriter"
+
+	"go-quickstart/money"
 )
 
+// locale used to format prices shown to the user.
+const locale = "es-CL"
+
 type searchParams struct {
 	Origen      string `json:"origen"`
 	Destino     string `json:"destino"`
 	FechaSalida string `json:"fecha"`
 	Adultos     string `json:"adultos"`
+	Moneda      string `json:"moneda"`
+}
+
+// DisplayPrice is a referential price converted by the server to the
+// currency chosen by the user. The offer is billed in BillingCurrency.
+type DisplayPrice struct {
+	Currency         string `json:"currency"`
+	Total            string `json:"total"`
+	GrandTotal       string `json:"grandTotal"`
+	BillingCurrency  string `json:"billingCurrency"`
+	BilledTotal      string `json:"billedTotal"`
+	BilledGrandTotal string `json:"billedGrandTotal"`
+	ExchangeRate     string `json:"exchangeRate"`
 }
 
 type FlightOffers struct {
@@ -25,6 +53,7 @@ type FlightOffers struct {
 		Type                     string `json:"type"`
 		ID                       string `json:"id"`
 		Source                   string `json:"source"`
+		Provider                 string `json:"provider,omitempty"`
 		InstantTicketingRequired bool   `json:"instantTicketingRequired"`
 		NonHomogeneous           bool   `json:"nonHomogeneous"`
 		OneWay                   bool   `json:"oneWay"`
@@ -66,6 +95,8 @@ type FlightOffers struct {
 			} `json:"fees"`
 			GrandTotal string `json:"grandTotal"`
 		} `json:"price"`
+		DisplayPrice   *DisplayPrice `json:"displayPrice,omitempty"`
+		Tags           []string      `json:"tags,omitempty"`
 		PricingOptions struct {
 			FareType                []string `json:"fareType"`
 			IncludedCheckedBagsOnly bool     `json:"includedCheckedBagsOnly"`
@@ -92,8 +123,16 @@ type FlightOffers struct {
 			} `json:"fareDetailsBySegment"`
 		} `json:"travelerPricings"`
 	} `json:"data"`
+	Meta struct {
+		Count  int `json:"count"`
+		Total  int `json:"total"`
+		Offset int `json:"offset"`
+	} `json:"meta"`
 }
 
+// pageSize is the number of offers requested per page of search results.
+const pageSize = 50
+
 type Traveler struct {
 	ID          string `json:"id"`
 	DateOfBirth string `json:"dateOfBirth"`
@@ -119,6 +158,23 @@ type BookingResponse struct {
 	} `json:"data"`
 }
 
+// Policy is the refund or change policy of a fare, as summarized by the server.
+type Policy struct {
+	Allowed *bool `json:"allowed"`
+	Fee     *struct {
+		Amount   string `json:"amount"`
+		Currency string `json:"currency"`
+	} `json:"fee"`
+}
+
+type FareRules struct {
+	SegmentID string   `json:"segmentId"`
+	FareBasis string   `json:"fareBasis"`
+	Refund    Policy   `json:"refund"`
+	Change    Policy   `json:"change"`
+	Validity  []string `json:"validity"`
+}
+
 type PricingResponse struct {
 	Data struct {
 		Type         string `json:"type"`
@@ -126,6 +182,7 @@ type PricingResponse struct {
 			Type                     string `json:"type"`
 			ID                       string `json:"id"`
 			Source                   string `json:"source"`
+			Provider                 string `json:"provider,omitempty"`
 			InstantTicketingRequired bool   `json:"instantTicketingRequired"`
 			NonHomogeneous           bool   `json:"nonHomogeneous"`
 			LastTicketingDate        string `json:"lastTicketingDate"`
@@ -163,6 +220,7 @@ type PricingResponse struct {
 				GrandTotal      string `json:"grandTotal"`
 				BillingCurrency string `json:"billingCurrency"`
 			} `json:"price"`
+			DisplayPrice   *DisplayPrice `json:"displayPrice,omitempty"`
 			PricingOptions struct {
 				FareType                []string `json:"fareType"`
 				IncludedCheckedBagsOnly bool     `json:"includedCheckedBagsOnly"`
@@ -193,6 +251,7 @@ type PricingResponse struct {
 			} `json:"travelerPricings"`
 		} `json:"flightOffers"`
 	} `json:"data"`
+	FareRules []FareRules `json:"fareRules"`
 }
 
 type OrderResponse struct {
@@ -245,52 +304,95 @@ type OrderResponse struct {
 				} `json:"segments"`
 			} `json:"itineraries"`
 			Price struct {
-				Total string `json:"total"`
+				Currency   string `json:"currency"`
+				Total      string `json:"total"`
+				GrandTotal string `json:"grandTotal"`
 			} `json:"price"`
 		} `json:"flightOffers"`
 	} `json:"data"`
 }
 
-func searchHandler() {
+// formatPrice renders an Amadeus price string for display, falling back to
+// the raw value if it cannot be parsed.
+func formatPrice(amount, currency string) string {
+	m, err := money.Parse(amount, currency)
+	if err != nil {
+		return strings.TrimSpace(amount + " " + currency)
+	}
+	return m.Format(locale)
+}
 
-	////// Searching For Flights //////
-	var search searchParams
-	fmt.Print("Aeropuerto de origen: ")
-	fmt.Scanln(&search.Origen)
-	fmt.Print("Aeropuerto de destino: ")
-	fmt.Scanln(&search.Destino)
-	fmt.Print("Fecha de salida: ")
-	fmt.Scanln(&search.FechaSalida)
-	fmt.Print("Cantidad de Adultos: ")
-	fmt.Scanln(&search.Adultos)
+// formatDisplayPrice renders a converted price, or "-" when the server could
+// not convert it.
+func formatDisplayPrice(displayPrice *DisplayPrice) string {
+	if displayPrice == nil {
+		return "-"
+	}
+	amount := displayPrice.GrandTotal
+	if amount == "" {
+		amount = displayPrice.Total
+	}
+	return formatPrice(amount, displayPrice.Currency)
+}
 
-	jsonData := fmt.Sprintf(`{"origen": "%s","destino": "%s","fecha": "%s","adultos": "%s"}`, search.Origen, search.Destino, search.FechaSalida, search.Adultos)
+// formatPolicy describes a refund or change policy in a table cell.
+func formatPolicy(policy Policy) string {
+	switch {
+	case policy.Allowed == nil:
+		return "Sin información"
+	case !*policy.Allowed:
+		return "No permitido"
+	case policy.Fee != nil:
+		return "Permitido, cargo " + formatPrice(policy.Fee.Amount, policy.Fee.Currency)
+	}
+	return "Permitido"
+}
 
-	var data = strings.NewReader(jsonData)
-	client := &http.Client{}
-	req, err := http.NewRequest("GET", "http://127.0.0.1:5000/api/search", data)
-	if err != nil {
-		log.Fatal(err)
+// tagNames translates the tags computed by the server.
+var tagNames = map[string]string{
+	"cheapest": "Más barato",
+	"fastest":  "Más rápido",
+	"best":     "Mejor opción",
+}
+
+// formatTags joins the translated tags of an offer.
+func formatTags(tags []string) string {
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if name, ok := tagNames[tag]; ok {
+			names = append(names, name)
+		}
 	}
-	req.Header.Set("Content-Type", "application/json")
+	return strings.Join(names, ", ")
+}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Fatal(err)
+// highlight returns the colors used for the rows of tagged offers.
+func highlight(columns int) []tablewriter.Colors {
+	colors := make([]tablewriter.Colors, columns)
+	for i := range colors {
+		colors[i] = tablewriter.Colors{tablewriter.Bold, tablewriter.FgGreenColor}
 	}
+	return colors
+}
 
-	var flightSearchResponse FlightOffers
-	err = json.NewDecoder(resp.Body).Decode(&flightSearchResponse)
-	if err != nil {
-		fmt.Println("Error decoding flight search response:", err)
+// printOffers renders one page of search results and how much of the full
+// result set it covers.
+func printOffers(offers FlightOffers, moneda string) {
+	meta := offers.Meta
+	if meta.Count == 0 {
+		fmt.Println("No se encontraron resultados.")
 		return
 	}
-	fmt.Println("Se obtuvieron los siguientes resultados:")
+	fmt.Printf("Mostrando resultados %d a %d de %d:\n", meta.Offset+1, meta.Offset+meta.Count, meta.Total)
 
 	table := tablewriter.NewWriter(os.Stdout)
 
-	table.SetHeader([]string{"VUELO", "NÚMERO", "HORA DE SALIDA", "HORA DE LLEGADA", "AVIÓN", "PRECIO TOTAL"})
-	for _, dataItem := range flightSearchResponse.Data {
+	header := []string{"VUELO", "PROVEEDOR", "NÚMERO", "HORA DE SALIDA", "HORA DE LLEGADA", "AVIÓN", "PRECIO TOTAL", "DESTACADO"}
+	if moneda != "" {
+		header = append(header, "PRECIO REFERENCIAL")
+	}
+	table.SetHeader(header)
+	for _, dataItem := range offers.Data {
 		for _, itinerary := range dataItem.Itineraries {
 			for _, segment := range itinerary.Segments {
 				id := dataItem.ID
@@ -301,18 +403,104 @@ func searchHandler() {
 				//carrierCode := segment.CarrierCode
 				flightNumber := segment.CarrierCode + segment.Number
 				aircraftCode := "A" + segment.Aircraft.Code
-				totalPrice := dataItem.Price.Total
-				table.Append([]string{id, flightNumber, departureTime, arrivalTime, aircraftCode, totalPrice})
+				totalPrice := formatPrice(dataItem.Price.Total, dataItem.Price.Currency)
+				row := []string{id, dataItem.Provider, flightNumber, departureTime, arrivalTime, aircraftCode, totalPrice, formatTags(dataItem.Tags)}
+				if moneda != "" {
+					row = append(row, formatDisplayPrice(dataItem.DisplayPrice))
+				}
+				if len(dataItem.Tags) > 0 {
+					table.Rich(row, highlight(len(row)))
+				} else {
+					table.Append(row)
+				}
 			}
 		}
 	}
 	table.Render()
+}
+
+func searchHandler() {
+
+	////// Searching For Flights //////
+	var search searchParams
+	fmt.Print("Aeropuerto de origen: ")
+	fmt.Scanln(&search.Origen)
+	fmt.Print("Aeropuerto de destino: ")
+	fmt.Scanln(&search.Destino)
+	fmt.Print("Fecha de salida: ")
+	fmt.Scanln(&search.FechaSalida)
+	fmt.Print("Cantidad de Adultos: ")
+	fmt.Scanln(&search.Adultos)
+	fmt.Print("Moneda para mostrar precios (opcional, ej. USD): ")
+	fmt.Scanln(&search.Moneda)
+	var orden string
+	fmt.Print("Ordenar por (price, duration, departure, arrival, stops; opcional): ")
+	fmt.Scanln(&orden)
+
+	jsonData := fmt.Sprintf(`{"origen": "%s","destino": "%s","fecha": "%s","adultos": "%s","moneda": "%s"}`, search.Origen, search.Destino, search.FechaSalida, search.Adultos, search.Moneda)
+
+	client := &http.Client{}
+	var flightSearchResponse FlightOffers
+	var flightID string
+	offset := 0
+	for {
+		query := url.Values{"sort": {orden}, "limit": {strconv.Itoa(pageSize)}, "offset": {strconv.Itoa(offset)}}
+		req, err := http.NewRequest("GET", "http://127.0.0.1:5000/api/search?"+query.Encode(), strings.NewReader(jsonData))
+		if err != nil {
+			log.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
 
-	// Selection of flight for booking
-	var flightID int
-	fmt.Print("Seleccione un vuelo (ingrese 0 para realizar nueva búsqueda): ")
-	fmt.Scanln(&flightID)
-	if flightID == 0 {
+		resp, err := client.Do(req)
+		if err != nil {
+			log.Fatal(err)
+		}
+		flightSearchResponse = FlightOffers{}
+		err = json.NewDecoder(resp.Body).Decode(&flightSearchResponse)
+		resp.Body.Close()
+		if err != nil {
+			fmt.Println("Error decoding flight search response:", err)
+			return
+		}
+		printOffers(flightSearchResponse, search.Moneda)
+
+		// Selection of flight for booking, or another page of results
+		meta := flightSearchResponse.Meta
+		hasNext := meta.Offset+meta.Count < meta.Total
+		hasPrev := meta.Offset > 0
+		prompt := "Seleccione un vuelo (ingrese 0 para realizar nueva búsqueda"
+		if hasNext {
+			prompt += ", s para la página siguiente"
+		}
+		if hasPrev {
+			prompt += ", a para la página anterior"
+		}
+		fmt.Print(prompt + "): ")
+		flightID = ""
+		fmt.Scanln(&flightID)
+		switch {
+		case flightID == "s" && hasNext:
+			offset = meta.Offset + meta.Count
+			continue
+		case flightID == "a" && hasPrev:
+			offset = max(meta.Offset-pageSize, 0)
+			continue
+		}
+		break
+	}
+	if flightID == "" || flightID == "0" {
+		return
+	}
+
+	// Results may be sorted, so look the offer up by ID instead of position.
+	selected := -1
+	for i, dataItem := range flightSearchResponse.Data {
+		if dataItem.ID == flightID {
+			selected = i
+		}
+	}
+	if selected == -1 {
+		fmt.Println("No existe un vuelo con ese número.")
 		return
 	}
 
@@ -320,16 +508,16 @@ func searchHandler() {
 	flightPriceData := map[string]interface{}{
 		"data": map[string]interface{}{
 			"type":         "flight-offers-pricing",
-			"flightOffers": []interface{}{flightSearchResponse.Data[flightID-1]},
+			"flightOffers": []interface{}{flightSearchResponse.Data[selected]},
 		},
 	}
 	pricingData, _ := json.Marshal(flightPriceData)
-	req, err = http.NewRequest("POST", "http://127.0.0.1:5000/api/pricing", bytes.NewBuffer(pricingData))
+	req, err := http.NewRequest("POST", "http://127.0.0.1:5000/api/pricing?fareRules=true&moneda="+url.QueryEscape(search.Moneda), bytes.NewBuffer(pricingData))
 	if err != nil {
 		log.Fatal(err)
 	}
 	req.Header.Set("Content-Type", "application/json")
-	resp, err = client.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -341,7 +529,27 @@ func searchHandler() {
 		fmt.Println("Error decoding flight pricing response:", err)
 		return
 	}
-	fmt.Println("El precio total final es de: ", pricingResponse.Data.FlightOffers[0].Price.Total)
+	finalPrice := pricingResponse.Data.FlightOffers[0].Price
+	fmt.Println("El precio total final es de: ", formatPrice(finalPrice.GrandTotal, finalPrice.Currency))
+	if displayPrice := pricingResponse.Data.FlightOffers[0].DisplayPrice; displayPrice != nil {
+		fmt.Println("Precio referencial (se cobra en", displayPrice.BillingCurrency+"):", formatDisplayPrice(displayPrice))
+	}
+
+	if len(pricingResponse.FareRules) > 0 {
+		fmt.Println("Condiciones de la tarifa:")
+		tableRules := tablewriter.NewWriter(os.Stdout)
+		tableRules.SetHeader([]string{"TRAMO", "TARIFA", "REEMBOLSO", "CAMBIOS", "VALIDEZ"})
+		for _, rules := range pricingResponse.FareRules {
+			tableRules.Append([]string{rules.SegmentID, rules.FareBasis, formatPolicy(rules.Refund), formatPolicy(rules.Change), strings.Join(rules.Validity, " ")})
+		}
+		tableRules.Render()
+	}
+	var confirm string
+	fmt.Print("¿Desea continuar con la reserva? (s/n): ")
+	fmt.Scanln(&confirm)
+	if strings.ToLower(confirm) != "s" {
+		return
+	}
 
 	//////  Booking flight //////
 	var travelers []Traveler
@@ -448,7 +656,7 @@ func GetBookingHandler() {
 				arrivalTime := dTime[strings.Index(aTime, "T")+1:]
 				flightNumber := segment.CarrierCode + segment.Number
 				aircraftCode := "A" + segment.Aircraft.Code
-				totalPrice := dataItem.Price.Total
+				totalPrice := formatPrice(dataItem.Price.Total, dataItem.Price.Currency)
 				tableFlight.Append([]string{flightNumber, departureTime, arrivalTime, aircraftCode, totalPrice})
 			}
 		}
@@ -466,13 +674,180 @@ func GetBookingHandler() {
 	tableTravelers.Render()
 }
 
+// DownloadItineraryHandler saves the itinerary of a booking as an .ics file
+// that can be imported in any calendar.
+func DownloadItineraryHandler() {
+
+	var orderID string
+	fmt.Print("Ingrese el ID de la reserva:")
+	fmt.Scanln(&orderID)
+
+	resp, err := http.Get("http://127.0.0.1:5000/api/booking/" + url.PathEscape(orderID) + "/ics")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		fmt.Println("No se pudo obtener el itinerario:", resp.Status)
+		return
+	}
+
+	filename := "reserva.ics"
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil && params["filename"] != "" {
+		filename = filepath.Base(params["filename"])
+	}
+	fmt.Print("Guardar como (", filename, "): ")
+	var path string
+	fmt.Scanln(&path)
+	if path == "" {
+		path = filename
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		fmt.Println("Error al crear el archivo:", err)
+		return
+	}
+	defer file.Close()
+	if _, err := io.Copy(file, resp.Body); err != nil {
+		fmt.Println("Error al guardar el itinerario:", err)
+		return
+	}
+	fmt.Println("Itinerario guardado en", path)
+}
+
+// streamSearchHandler searches several departure dates at once and prints
+// the offers of each date as soon as the server sends them.
+func streamSearchHandler() {
+	var search searchParams
+	fmt.Print("Aeropuerto de origen: ")
+	fmt.Scanln(&search.Origen)
+	fmt.Print("Aeropuerto de destino: ")
+	fmt.Scanln(&search.Destino)
+	fmt.Print("Fechas de salida, separadas por coma: ")
+	fmt.Scanln(&search.FechaSalida)
+	fmt.Print("Cantidad de Adultos: ")
+	fmt.Scanln(&search.Adultos)
+	fmt.Print("Moneda para mostrar precios (opcional, ej. USD): ")
+	fmt.Scanln(&search.Moneda)
+
+	query := url.Values{}
+	query.Set("origen", search.Origen)
+	query.Set("destino", search.Destino)
+	query.Set("fecha", search.FechaSalida)
+	query.Set("adultos", search.Adultos)
+	query.Set("moneda", search.Moneda)
+	query.Set("sort", "price")
+	req, err := http.NewRequest("GET", "http://127.0.0.1:5000/api/search/stream?"+query.Encode(), nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var apiError struct {
+			Error string `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&apiError)
+		fmt.Println("No se pudo buscar:", apiError.Error)
+		return
+	}
+
+	row := "%-10s  %-6s  %-8s  %-8s  %-8s  %-18s  %s\n"
+	fmt.Printf(row, "FECHA", "VUELO", "NÚMERO", "SALIDA", "LLEGADA", "PRECIO TOTAL", "PRECIO REFERENCIAL")
+	clock := func(at string) string {
+		return at[strings.Index(at, "T")+1:]
+	}
+
+	// Each event is an "event:" line and a "data:" line followed by a blank
+	// line.
+	var event, data string
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64<<10), 16<<20)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if name, ok := strings.CutPrefix(line, "event:"); ok {
+			event = name
+			continue
+		}
+		if value, ok := strings.CutPrefix(line, "data:"); ok {
+			data = value
+			continue
+		}
+		if line != "" {
+			continue
+		}
+
+		switch event {
+		case "offers":
+			var batch struct {
+				Batch  string          `json:"batch"`
+				Offers json.RawMessage `json:"offers"`
+			}
+			var offers FlightOffers
+			if json.Unmarshal([]byte(data), &batch) != nil || json.Unmarshal(batch.Offers, &offers.Data) != nil {
+				fmt.Println("Respuesta inválida del servidor.")
+				continue
+			}
+			for _, offer := range offers.Data {
+				for _, itinerary := range offer.Itineraries {
+					for _, segment := range itinerary.Segments {
+						fmt.Printf(row, batch.Batch, offer.ID, segment.CarrierCode+segment.Number,
+							clock(segment.Departure.At), clock(segment.Arrival.At),
+							formatPrice(offer.Price.Total, offer.Price.Currency), formatDisplayPrice(offer.DisplayPrice))
+					}
+				}
+			}
+		case "error":
+			var failure struct {
+				Batch string `json:"batch"`
+				Error string `json:"error"`
+			}
+			json.Unmarshal([]byte(data), &failure)
+			fmt.Printf("Falló la búsqueda del %s: %s\n", failure.Batch, failure.Error)
+		case "progress":
+			var progress struct {
+				Completed int `json:"completed"`
+				Total     int `json:"total"`
+			}
+			json.Unmarshal([]byte(data), &progress)
+			fmt.Printf("... %d de %d búsquedas listas\n", progress.Completed, progress.Total)
+		case "done":
+			var done struct {
+				Offers int `json:"offers"`
+				Tags   map[string]struct {
+					Batch string `json:"batch"`
+					ID    string `json:"id"`
+				} `json:"tags"`
+			}
+			json.Unmarshal([]byte(data), &done)
+			fmt.Printf("Se encontraron %d ofertas.\n", done.Offers)
+			for _, tag := range []string{"cheapest", "fastest", "best"} {
+				if ref, ok := done.Tags[tag]; ok {
+					fmt.Printf("%s: vuelo %s del %s\n", tagNames[tag], ref.ID, ref.Batch)
+				}
+			}
+		}
+		event, data = "", ""
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Println("Se interrumpió la búsqueda:", err)
+	}
+}
+
 func main() {
 	initText := `Bievenido a goTravel!`
 	fmt.Print(initText)
 	text := `
 1. Realizar búsqueda.
 2. Obtener reserva.
-3. Salir
+3. Descargar itinerario (.ics).
+4. Búsqueda en varias fechas, con resultados en vivo.
+5. Salir
 Ingrese una opción:`
 
 	for {
@@ -486,11 +861,15 @@ Ingrese una opción:`
 		case "2":
 			GetBookingHandler()
 		case "3":
+			DownloadItineraryHandler()
+		case "4":
+			streamSearchHandler()
+		case "5":
 			fmt.Println("Gracias por usar goTravel!")
 			return
 
-		default: // if the command is not 1, 2 or 3
-			fmt.Println("Por favor, ingrese 1, 2 o 3") // print an error message
+		default: // if the command is not 1 to 5
+			fmt.Println("Por favor, ingrese un número del 1 al 5") // print an error message
 		}
 	}
 }
@@ -0,0 +1,539 @@
+// Package config loads the server configuration once at startup.
+//
+// Values are layered, each source overriding the previous one:
+//
+//  1. defaults (Default)
+//  2. an optional YAML file given with -config or CONFIG_FILE
+//     (see config.example.yaml)
+//  3. the encrypted secrets file given with -secrets-file or SECRETS_FILE,
+//     unlocked with the key in SECRETS_KEY or SECRETS_KEY_FILE
+//  4. environment variables, including those in local.env when present
+//     (copy local.env.example; local.env is not tracked by git)
+//  5. command-line flags
+//
+// Every field documents its YAML key, environment variable and flag in its
+// struct tags. Load validates the result, so a misconfigured server fails at
+// startup instead of on the first request.
+//
+// Credentials are Secret fields. They have no flags, since arguments are
+// visible to every user of the host, and each can also be read from a file
+// named by the variable with a _FILE suffix (CLIENT_ID_FILE, SECRET_ID_FILE,
+// MONGO_PASSWORD_FILE, ...), as mounted by Docker and Kubernetes secrets.
+// Run with -env-file="" to read the environment only.
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+
+	"go-quickstart/search"
+	"go-quickstart/secrets"
+)
+
+// Amadeus environments.
+const (
+	EnvironmentTest       = "test"
+	EnvironmentProduction = "production"
+)
+
+var baseURLs = map[string]string{
+	EnvironmentTest:       "https://test.api.amadeus.com",
+	EnvironmentProduction: "https://api.amadeus.com",
+}
+
+// Flight providers.
+const (
+	ProviderAmadeus = "amadeus"
+	ProviderDuffel  = "duffel"
+)
+
+// ErrInvalid wraps every validation error.
+var ErrInvalid = errors.New("config: invalid configuration")
+
+type Config struct {
+	Server    Server    `yaml:"server"`
+	Amadeus   Amadeus   `yaml:"amadeus"`
+	Duffel    Duffel    `yaml:"duffel"`
+	Mongo     Mongo     `yaml:"mongo"`
+	Search    Search    `yaml:"search"`
+	Webhooks  Webhooks  `yaml:"webhooks"`
+	SMTP      SMTP      `yaml:"smtp"`
+	Watches   Watches   `yaml:"watches"`
+	Readiness Readiness `yaml:"readiness"`
+
+	AdminToken  Secret        `yaml:"adminToken" env:"ADMIN_TOKEN"`
+	SecretsFile string        `yaml:"secretsFile" env:"SECRETS_FILE" flag:"secrets-file" usage:"encrypted secrets file"`
+	RatesFile   string        `yaml:"ratesFile" env:"RATES_FILE" flag:"rates-file" usage:"exchange-rate table stored at startup"`
+	RatesTTL    time.Duration `yaml:"ratesTTL" env:"RATES_TTL" flag:"rates-ttl" usage:"time exchange rates are used before being reloaded from MongoDB"`
+	LogLevel    string        `yaml:"logLevel" env:"LOG_LEVEL" flag:"log-level" usage:"debug, info, warn or error"`
+
+	// Print asks main to print the configuration, with secrets masked, and
+	// exit.
+	Print bool `yaml:"-" flag:"print-config" usage:"print the configuration with secrets masked and exit"`
+}
+
+type Server struct {
+	Host            string        `yaml:"host" env:"SERVER" flag:"host" usage:"address to listen on"`
+	Port            string        `yaml:"port" env:"PORT" flag:"port" usage:"port to listen on"`
+	GRPCPort        string        `yaml:"grpcPort" env:"GRPC_PORT" flag:"grpc-port" usage:"port the gRPC API listens on; empty disables it"`
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout" env:"SHUTDOWN_TIMEOUT" flag:"shutdown-timeout" usage:"time given to in-flight requests on shutdown"`
+	DrainDelay      time.Duration `yaml:"drainDelay" env:"SHUTDOWN_DRAIN_DELAY" flag:"drain-delay" usage:"time readiness fails before the listener closes"`
+}
+
+// Addr is the listen address.
+func (s Server) Addr() string {
+	return s.Host + ":" + s.Port
+}
+
+// GRPCAddr is the listen address of the gRPC API.
+func (s Server) GRPCAddr() string {
+	return s.Host + ":" + s.GRPCPort
+}
+
+type Amadeus struct {
+	Environment     string        `yaml:"environment" env:"AMADEUS_ENV" flag:"amadeus-env" usage:"test or production"`
+	BaseURL         string        `yaml:"baseURL" env:"AMADEUS_BASE_URL" flag:"amadeus-base-url" usage:"overrides the URL of the environment"`
+	ClientID        Secret        `yaml:"clientID" env:"CLIENT_ID"`
+	ClientSecret    Secret        `yaml:"clientSecret" env:"SECRET_ID"`
+	Timeout         time.Duration `yaml:"timeout" env:"AMADEUS_TIMEOUT" flag:"amadeus-timeout" usage:"timeout of each call to Amadeus"`
+	MaxAttempts     int           `yaml:"maxAttempts" env:"AMADEUS_MAX_ATTEMPTS" flag:"amadeus-max-attempts" usage:"attempts per Amadeus call, including the first"`
+	BreakerCooldown time.Duration `yaml:"breakerCooldown" env:"AMADEUS_BREAKER_COOLDOWN" flag:"amadeus-breaker-cooldown" usage:"time Amadeus is not called after repeated failures"`
+	Record          string        `yaml:"record" env:"AMADEUS_RECORD" flag:"record" usage:"cassette file every Amadeus call is appended to"`
+	Replay          []string      `yaml:"replay" env:"AMADEUS_REPLAY" flag:"replay" usage:"comma-separated cassette files or directories Amadeus calls are answered from"`
+}
+
+// URL joins a path to the base URL.
+func (a Amadeus) URL(path string) string {
+	return strings.TrimRight(a.BaseURL, "/") + path
+}
+
+type Duffel struct {
+	BaseURL         string        `yaml:"baseURL" env:"DUFFEL_BASE_URL" flag:"duffel-base-url" usage:"URL of the Duffel API"`
+	AccessToken     Secret        `yaml:"accessToken" env:"DUFFEL_ACCESS_TOKEN"`
+	Timeout         time.Duration `yaml:"timeout" env:"DUFFEL_TIMEOUT" flag:"duffel-timeout" usage:"timeout of each call to Duffel"`
+	MaxAttempts     int           `yaml:"maxAttempts" env:"DUFFEL_MAX_ATTEMPTS" flag:"duffel-max-attempts" usage:"attempts per Duffel call, including the first"`
+	BreakerCooldown time.Duration `yaml:"breakerCooldown" env:"DUFFEL_BREAKER_COOLDOWN" flag:"duffel-breaker-cooldown" usage:"time Duffel is not called after repeated failures"`
+}
+
+// URL joins a path to the base URL.
+func (d Duffel) URL(path string) string {
+	return strings.TrimRight(d.BaseURL, "/") + path
+}
+
+type Mongo struct {
+	URI                Secret        `yaml:"uri" env:"CONNECTION_STRING"`
+	Password           Secret        `yaml:"password" env:"MONGO_PASSWORD"`
+	Database           string        `yaml:"database" env:"MONGO_DATABASE" flag:"mongo-database" usage:"MongoDB database"`
+	BookingsCollection string        `yaml:"bookingsCollection" env:"MONGO_BOOKINGS_COLLECTION" flag:"mongo-bookings-collection" usage:"collection that stores bookings"`
+	Timeout            time.Duration `yaml:"timeout" env:"MONGO_TIMEOUT" flag:"mongo-timeout" usage:"timeout of each MongoDB operation"`
+}
+
+// ConnectionString is the URI with the password, when set separately, added
+// to its credentials.
+func (m Mongo) ConnectionString() (string, error) {
+	if m.Password == "" {
+		return m.URI.Reveal(), nil
+	}
+	u, err := url.Parse(m.URI.Reveal())
+	if err != nil {
+		// The error would include the URI.
+		return "", errors.New("mongo URI is not a valid URI")
+	}
+	if u.User == nil || u.User.Username() == "" {
+		return "", errors.New("mongo URI has no user for the password")
+	}
+	u.User = url.UserPassword(u.User.Username(), m.Password.Reveal())
+	return u.String(), nil
+}
+
+type Search struct {
+	Providers   []string `yaml:"providers" env:"SEARCH_PROVIDERS" flag:"providers" usage:"comma-separated flight providers searched: amadeus, duffel"`
+	Currency    string   `yaml:"currency" env:"BILLING_CURRENCY" flag:"currency" usage:"currency offers are priced in"`
+	Airlines    []string `yaml:"airlines" env:"SEARCH_AIRLINES" flag:"airlines" usage:"comma-separated airlines searched"`
+	NonStop     bool     `yaml:"nonStop" env:"SEARCH_NON_STOP" flag:"non-stop" usage:"search direct flights only"`
+	TravelClass string   `yaml:"travelClass" env:"SEARCH_TRAVEL_CLASS" flag:"travel-class" usage:"ECONOMY, PREMIUM_ECONOMY, BUSINESS or FIRST"`
+	BestWeights string   `yaml:"bestWeights" env:"BEST_WEIGHTS" flag:"best-weights" usage:"weights of the best-offer score"`
+}
+
+type Webhooks struct {
+	Timeout time.Duration `yaml:"timeout" env:"WEBHOOK_TIMEOUT" flag:"webhook-timeout" usage:"timeout of each webhook delivery"`
+}
+
+type SMTP struct {
+	Host     string        `yaml:"host" env:"SMTP_HOST"`
+	Port     string        `yaml:"port" env:"SMTP_PORT"`
+	Username string        `yaml:"username" env:"SMTP_USERNAME"`
+	Password Secret        `yaml:"password" env:"SMTP_PASSWORD"`
+	From     string        `yaml:"from" env:"SMTP_FROM"`
+	Timeout  time.Duration `yaml:"timeout" env:"SMTP_TIMEOUT"`
+}
+
+type Watches struct {
+	Interval  time.Duration `yaml:"interval" env:"WATCH_INTERVAL" flag:"watch-interval" usage:"time between rounds of price watch checks"`
+	Jitter    time.Duration `yaml:"jitter" env:"WATCH_JITTER"`
+	RateLimit time.Duration `yaml:"rateLimit" env:"WATCH_RATE_LIMIT"`
+	// APIKeys lists the customers allowed to create price watches, as
+	// comma-separated owner:key pairs. Each sees only their own watches.
+	APIKeys     Secret `yaml:"apiKeys" env:"WATCH_API_KEYS"`
+	MaxPerOwner int    `yaml:"maxPerOwner" env:"WATCH_MAX_PER_OWNER" flag:"watch-max-per-owner" usage:"price watches each owner may have"`
+}
+
+// Owners returns the owner of each API key.
+func (w Watches) Owners() (map[string]string, error) {
+	owners := map[string]string{}
+	for _, pair := range strings.Split(w.APIKeys.Reveal(), ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		owner, key, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || owner == "" || key == "" {
+			return nil, fmt.Errorf("%w: watch API keys must be owner:key pairs", ErrInvalid)
+		}
+		if _, dup := owners[key]; dup {
+			return nil, fmt.Errorf("%w: watch API key of %q is also another owner's", ErrInvalid, owner)
+		}
+		owners[key] = owner
+	}
+	return owners, nil
+}
+
+type Readiness struct {
+	CacheTTL     time.Duration `yaml:"cacheTTL" env:"READY_CACHE_TTL"`
+	CheckTimeout time.Duration `yaml:"checkTimeout" env:"READY_CHECK_TIMEOUT"`
+}
+
+// Default returns the configuration used when nothing overrides it.
+func Default() Config {
+	return Config{
+		Server: Server{
+			Port:            "8080",
+			ShutdownTimeout: 15 * time.Second,
+		},
+		Amadeus: Amadeus{
+			Environment:     EnvironmentTest,
+			Timeout:         20 * time.Second,
+			MaxAttempts:     4,
+			BreakerCooldown: 30 * time.Second,
+		},
+		Duffel: Duffel{
+			BaseURL:         "https://api.duffel.com",
+			Timeout:         20 * time.Second,
+			MaxAttempts:     3,
+			BreakerCooldown: 30 * time.Second,
+		},
+		Mongo: Mongo{
+			Database:           "gotravel",
+			BookingsCollection: "reservations",
+			Timeout:            5 * time.Second,
+		},
+		Search: Search{
+			Providers:   []string{ProviderAmadeus},
+			Currency:    "CLP",
+			Airlines:    []string{"LA", "JA", "H2"},
+			NonStop:     true,
+			TravelClass: "ECONOMY",
+		},
+		Webhooks: Webhooks{Timeout: 10 * time.Second},
+		SMTP:     SMTP{Port: "587", From: "reservas@gotravel.local", Timeout: 30 * time.Second},
+		Watches: Watches{
+			Interval:    30 * time.Minute,
+			Jitter:      5 * time.Minute,
+			RateLimit:   2 * time.Second,
+			MaxPerOwner: 20,
+		},
+		Readiness: Readiness{CacheTTL: 10 * time.Second, CheckTimeout: 3 * time.Second},
+		RatesTTL:  10 * time.Minute,
+		LogLevel:  "info",
+	}
+}
+
+// Load builds the configuration from the command-line arguments (without
+// the program name), the environment and the optional YAML file.
+func Load(args []string) (Config, error) {
+	cfg := Default()
+
+	fs := flag.NewFlagSet("gotravel", flag.ContinueOnError)
+	configFile := fs.String("config", "", "YAML configuration file")
+	envFile := fs.String("env-file", "local.env", "file with environment variables, if it exists")
+	flags := map[string]*flagValue{}
+	eachField(&cfg, func(field reflect.StructField, value reflect.Value) {
+		if name := field.Tag.Get("flag"); name != "" {
+			flags[name] = newFlagValue(value)
+			fs.Var(flags[name], name, field.Tag.Get("usage"))
+		}
+	})
+	if err := fs.Parse(args); err != nil {
+		return Config{}, err
+	}
+
+	// Variables already in the environment win over the file.
+	if *envFile != "" {
+		if err := godotenv.Load(*envFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return Config{}, fmt.Errorf("config: reading %s: %w", *envFile, err)
+		}
+	}
+
+	path := *configFile
+	if path == "" {
+		path = os.Getenv("CONFIG_FILE")
+	}
+	if path != "" {
+		if err := loadYAML(path, &cfg); err != nil {
+			return Config{}, err
+		}
+	}
+
+	visited := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { visited[f.Name] = true })
+
+	secretsFile := cfg.SecretsFile
+	if s := os.Getenv("SECRETS_FILE"); s != "" {
+		secretsFile = s
+	}
+	if visited["secrets-file"] {
+		secretsFile = flags["secrets-file"].value
+	}
+	sealed := map[string]string{}
+	if secretsFile != "" {
+		key, err := secrets.KeyFromEnv()
+		if err != nil {
+			return Config{}, fmt.Errorf("config: %w", err)
+		}
+		if key == "" {
+			return Config{}, fmt.Errorf("%w: SECRETS_KEY or SECRETS_KEY_FILE is required to open %s", ErrInvalid, secretsFile)
+		}
+		if sealed, err = secrets.ReadFile(secretsFile, key); err != nil {
+			return Config{}, fmt.Errorf("config: %w", err)
+		}
+	}
+
+	var errs []error
+	eachField(&cfg, func(field reflect.StructField, value reflect.Value) {
+		name := field.Tag.Get("env")
+		if name == "" {
+			return
+		}
+		s := sealed[name]
+		if v, ok := os.LookupEnv(name); ok && v != "" {
+			s = v
+		}
+		if field.Type == secretType {
+			v, err := lookupFile(name)
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+			if v != "" {
+				s = v
+			}
+		}
+		if s != "" {
+			if err := setValue(value, s); err != nil {
+				errs = append(errs, fmt.Errorf("%w: %s: %v", ErrInvalid, name, err))
+			}
+		}
+	})
+	eachField(&cfg, func(field reflect.StructField, value reflect.Value) {
+		if name := field.Tag.Get("flag"); visited[name] {
+			if err := setValue(value, flags[name].value); err != nil {
+				errs = append(errs, fmt.Errorf("%w: -%s: %v", ErrInvalid, name, err))
+			}
+		}
+	})
+	if err := errors.Join(errs...); err != nil {
+		return Config{}, err
+	}
+
+	if cfg.Amadeus.BaseURL == "" {
+		cfg.Amadeus.BaseURL = baseURLs[cfg.Amadeus.Environment]
+	}
+	return cfg, cfg.Validate()
+}
+
+// lookupFile returns the contents of the file named by name+"_FILE",
+// without the trailing newline, or the value of name itself.
+func lookupFile(name string) (string, error) {
+	path := os.Getenv(name + "_FILE")
+	if path == "" {
+		return os.Getenv(name), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("config: %s_FILE: %w", name, err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+func loadYAML(path string, cfg *Config) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	defer f.Close()
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil {
+		return fmt.Errorf("config: %s: %w", path, err)
+	}
+	return nil
+}
+
+// Validate checks that the configuration is complete and consistent.
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf("%w: "+format, append([]any{ErrInvalid}, args...)...))
+		}
+	}
+	if port, err := strconv.Atoi(c.Server.Port); err != nil || port < 0 || port > 65535 {
+		check(false, "port %q is not a valid port number", c.Server.Port)
+	}
+	if c.Server.GRPCPort != "" {
+		port, err := strconv.Atoi(c.Server.GRPCPort)
+		check(err == nil && port >= 0 && port <= 65535, "grpc port %q is not a valid port number", c.Server.GRPCPort)
+		check(c.Server.GRPCPort != c.Server.Port, "grpc port %q is also the HTTP port", c.Server.GRPCPort)
+	}
+	_, known := baseURLs[c.Amadeus.Environment]
+	check(known, "amadeus environment must be %q or %q, not %q", EnvironmentTest, EnvironmentProduction, c.Amadeus.Environment)
+	if known || c.Amadeus.BaseURL != "" {
+		check(strings.HasPrefix(c.Amadeus.BaseURL, "https://") || strings.HasPrefix(c.Amadeus.BaseURL, "http://"),
+			"amadeus base URL %q must be an http(s) URL", c.Amadeus.BaseURL)
+	}
+	check(c.Amadeus.Record == "" || len(c.Amadeus.Replay) == 0, "amadeus calls cannot be recorded and replayed at once")
+	// Replayed calls never reach Amadeus, so they need no credentials.
+	check(len(c.Amadeus.Replay) > 0 || c.Amadeus.ClientID != "" && c.Amadeus.ClientSecret != "",
+		"amadeus credentials are required (CLIENT_ID and SECRET_ID)")
+	check(c.Amadeus.MaxAttempts >= 1, "amadeus max attempts must be at least 1")
+	check(len(c.Search.Providers) > 0, "at least one flight provider must be searched")
+	searched := map[string]bool{}
+	for _, provider := range c.Search.Providers {
+		check(provider == ProviderAmadeus || provider == ProviderDuffel, "unknown flight provider %q", provider)
+		check(!searched[provider], "flight provider %q is listed twice", provider)
+		searched[provider] = true
+	}
+	if searched[ProviderDuffel] {
+		check(strings.HasPrefix(c.Duffel.BaseURL, "https://") || strings.HasPrefix(c.Duffel.BaseURL, "http://"),
+			"duffel base URL %q must be an http(s) URL", c.Duffel.BaseURL)
+		check(c.Duffel.AccessToken != "", "duffel access token is required (DUFFEL_ACCESS_TOKEN)")
+		check(c.Duffel.MaxAttempts >= 1, "duffel max attempts must be at least 1")
+	}
+	check(c.Mongo.URI != "", "mongo URI is required (CONNECTION_STRING)")
+	if c.Mongo.URI != "" {
+		_, err := c.Mongo.ConnectionString()
+		check(err == nil, "%v", err)
+	}
+	check(c.Mongo.Database != "" && c.Mongo.BookingsCollection != "", "mongo database and bookings collection are required")
+	check(len(c.Search.Currency) == 3, "billing currency %q must be an ISO 4217 code", c.Search.Currency)
+	check(len(c.Search.Airlines) > 0, "at least one airline must be searched")
+	switch c.Search.TravelClass {
+	case "ECONOMY", "PREMIUM_ECONOMY", "BUSINESS", "FIRST":
+	default:
+		check(false, "unknown travel class %q", c.Search.TravelClass)
+	}
+	if _, err := c.Watches.Owners(); err != nil {
+		errs = append(errs, err)
+	}
+	check(c.Watches.MaxPerOwner >= 1, "price watches per owner must be at least 1")
+	if c.Search.BestWeights != "" {
+		_, err := search.ParseWeights(c.Search.BestWeights)
+		check(err == nil, "best weights: %v", err)
+	}
+	for name, d := range map[string]time.Duration{
+		"amadeus timeout":       c.Amadeus.Timeout,
+		"duffel timeout":        c.Duffel.Timeout,
+		"mongo timeout":         c.Mongo.Timeout,
+		"webhook timeout":       c.Webhooks.Timeout,
+		"smtp timeout":          c.SMTP.Timeout,
+		"shutdown timeout":      c.Server.ShutdownTimeout,
+		"watch interval":        c.Watches.Interval,
+		"readiness cache TTL":   c.Readiness.CacheTTL,
+		"readiness check limit": c.Readiness.CheckTimeout,
+		"rates TTL":             c.RatesTTL,
+	} {
+		check(d > 0, "%s must be positive", name)
+	}
+	return errors.Join(errs...)
+}
+
+// eachField calls fn for every leaf field of the configuration.
+func eachField(cfg *Config, fn func(reflect.StructField, reflect.Value)) {
+	var walk func(v reflect.Value)
+	walk = func(v reflect.Value) {
+		for i := 0; i < v.NumField(); i++ {
+			field, value := v.Type().Field(i), v.Field(i)
+			if value.Kind() == reflect.Struct && field.Type != reflect.TypeOf(time.Duration(0)) {
+				walk(value)
+				continue
+			}
+			fn(field, value)
+		}
+	}
+	walk(reflect.ValueOf(cfg).Elem())
+}
+
+// flagValue holds a flag until it is applied over the environment. Its
+// default is the value of the field it sets.
+type flagValue struct {
+	value  string
+	isBool bool
+}
+
+func newFlagValue(field reflect.Value) *flagValue {
+	value := fmt.Sprint(field.Interface())
+	if list, ok := field.Interface().([]string); ok {
+		value = strings.Join(list, ",")
+	}
+	return &flagValue{value: value, isBool: field.Kind() == reflect.Bool}
+}
+
+func (f *flagValue) String() string     { return f.value }
+func (f *flagValue) Set(s string) error { f.value = s; return nil }
+func (f *flagValue) IsBoolFlag() bool   { return f.isBool }
+
+// setValue parses s into a string, int, bool, duration or string list field.
+func setValue(v reflect.Value, s string) error {
+	switch {
+	case v.Type() == reflect.TypeOf(time.Duration(0)):
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+	case v.Kind() == reflect.String:
+		v.SetString(s)
+	case v.Kind() == reflect.Int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(n))
+	case v.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String:
+		var list []string
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		v.Set(reflect.ValueOf(list))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,237 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go-quickstart/secrets"
+)
+
+// isolate clears every variable Load reads, so the tests do not depend on
+// the environment they run in, and sets the required credentials.
+func isolate(t *testing.T) {
+	t.Helper()
+	cfg := Default()
+	eachField(&cfg, func(field reflect.StructField, _ reflect.Value) {
+		if name := field.Tag.Get("env"); name != "" {
+			t.Setenv(name, "")
+			t.Setenv(name+"_FILE", "")
+		}
+	})
+	for _, name := range []string{"CONFIG_FILE", "SECRETS_KEY", "SECRETS_KEY_FILE"} {
+		t.Setenv(name, "")
+	}
+	t.Setenv("CLIENT_ID", "client")
+	t.Setenv("SECRET_ID", "secret")
+	t.Setenv("CONNECTION_STRING", "mongodb://gotravel@localhost:27017")
+}
+
+func write(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// seal writes a secrets file with values and makes its key available.
+func seal(t *testing.T, values map[string]string) string {
+	t.Helper()
+	key, err := secrets.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "secrets.enc")
+	if err := secrets.WriteFile(path, key, values); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SECRETS_KEY", key)
+	return path
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	tests := []struct {
+		name                          string
+		yaml, sealed, env, flag, want string
+	}{
+		{name: "default", want: "8080"},
+		{name: "yaml over default", yaml: "1001", want: "1001"},
+		{name: "secrets over yaml", yaml: "1001", sealed: "1002", want: "1002"},
+		{name: "env over secrets", yaml: "1001", sealed: "1002", env: "1003", want: "1003"},
+		{name: "flag over env", yaml: "1001", sealed: "1002", env: "1003", flag: "1004", want: "1004"},
+		{name: "flag over yaml", yaml: "1001", flag: "1004", want: "1004"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isolate(t)
+			args := []string{"-env-file="}
+			if tt.yaml != "" {
+				args = append(args, "-config", write(t, "config.yaml", "server:\n  port: \""+tt.yaml+"\"\n"))
+			}
+			if tt.sealed != "" {
+				args = append(args, "-secrets-file", seal(t, map[string]string{"PORT": tt.sealed}))
+			}
+			if tt.env != "" {
+				t.Setenv("PORT", tt.env)
+			}
+			if tt.flag != "" {
+				args = append(args, "-port", tt.flag)
+			}
+			cfg, err := Load(args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Server.Port != tt.want {
+				t.Errorf("port = %q, want %q", cfg.Server.Port, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadSecrets(t *testing.T) {
+	isolate(t)
+	t.Setenv("CLIENT_ID", "")
+	t.Setenv("SECRET_ID", "from-env")
+	t.Setenv("SECRETS_FILE", seal(t, map[string]string{"CLIENT_ID": "sealed-id", "SECRET_ID": "sealed-secret"}))
+	t.Setenv("ADMIN_TOKEN", "from-env")
+	t.Setenv("ADMIN_TOKEN_FILE", write(t, "admin-token", "from-file\n"))
+
+	cfg, err := Load([]string{"-env-file="})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Amadeus.ClientID != "sealed-id" {
+		t.Errorf("client ID = %q, want the sealed one", cfg.Amadeus.ClientID.Reveal())
+	}
+	if cfg.Amadeus.ClientSecret != "from-env" {
+		t.Errorf("client secret = %q, want the environment's", cfg.Amadeus.ClientSecret.Reveal())
+	}
+	if cfg.AdminToken != "from-file" {
+		t.Errorf("admin token = %q, want the _FILE contents without the newline", cfg.AdminToken.Reveal())
+	}
+
+	t.Setenv("ADMIN_TOKEN_FILE", filepath.Join(t.TempDir(), "missing"))
+	if _, err := Load([]string{"-env-file="}); err == nil || !strings.Contains(err.Error(), "ADMIN_TOKEN_FILE") {
+		t.Errorf("missing _FILE: err = %v", err)
+	}
+
+	t.Setenv("ADMIN_TOKEN_FILE", "")
+	t.Setenv("SECRETS_KEY", "")
+	if _, err := Load([]string{"-env-file="}); !errors.Is(err, ErrInvalid) || !strings.Contains(err.Error(), "SECRETS_KEY") {
+		t.Errorf("secrets file without key: err = %v", err)
+	}
+}
+
+func TestLoadParsesValues(t *testing.T) {
+	isolate(t)
+	t.Setenv("SEARCH_AIRLINES", " LA, ,JA ")
+	t.Setenv("WATCH_INTERVAL", "90s")
+	t.Setenv("AMADEUS_MAX_ATTEMPTS", "2")
+	t.Setenv("DUFFEL_BREAKER_COOLDOWN", "1m")
+	t.Setenv("SEARCH_NON_STOP", "true")
+	t.Setenv("AMADEUS_ENV", EnvironmentProduction)
+
+	cfg, err := Load([]string{"-env-file=", "-non-stop=false", "-replay", "a.jsonl,b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.Search.Airlines; !reflect.DeepEqual(got, []string{"LA", "JA"}) {
+		t.Errorf("airlines = %q", got)
+	}
+	if cfg.Watches.Interval != 90*time.Second || cfg.Amadeus.MaxAttempts != 2 || cfg.Search.NonStop {
+		t.Errorf("interval %v, attempts %d, non-stop %v", cfg.Watches.Interval, cfg.Amadeus.MaxAttempts, cfg.Search.NonStop)
+	}
+	if cfg.Duffel.BreakerCooldown != time.Minute || cfg.Duffel.MaxAttempts != 3 {
+		t.Errorf("duffel cooldown %v, attempts %d", cfg.Duffel.BreakerCooldown, cfg.Duffel.MaxAttempts)
+	}
+	if got := cfg.Amadeus.Replay; !reflect.DeepEqual(got, []string{"a.jsonl", "b"}) {
+		t.Errorf("replay = %q", got)
+	}
+	if cfg.Amadeus.BaseURL != baseURLs[EnvironmentProduction] {
+		t.Errorf("base URL = %q, want the production one", cfg.Amadeus.BaseURL)
+	}
+
+	for name, value := range map[string]string{
+		"WATCH_INTERVAL":       "half an hour",
+		"AMADEUS_MAX_ATTEMPTS": "many",
+		"SEARCH_NON_STOP":      "sometimes",
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(name, value)
+			if _, err := Load([]string{"-env-file="}); !errors.Is(err, ErrInvalid) || !strings.Contains(err.Error(), name) {
+				t.Errorf("err = %v, want one naming %s", err, name)
+			}
+		})
+	}
+
+	if _, err := Load([]string{"-env-file=", "-config", write(t, "config.yaml", "server:\n  prot: \"80\"\n")}); err == nil {
+		t.Error("unknown YAML key was accepted")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := func() Config {
+		cfg := Default()
+		cfg.Amadeus.BaseURL = baseURLs[cfg.Amadeus.Environment]
+		cfg.Amadeus.ClientID, cfg.Amadeus.ClientSecret = "client", "secret"
+		cfg.Mongo.URI = "mongodb://gotravel@localhost:27017"
+		return cfg
+	}
+	if err := valid().Validate(); err != nil {
+		t.Fatalf("valid configuration: %v", err)
+	}
+
+	tests := []struct {
+		want   string
+		modify func(*Config)
+	}{
+		{"port", func(c *Config) { c.Server.Port = "http" }},
+		{"grpc port", func(c *Config) { c.Server.GRPCPort = "70000" }},
+		{"also the HTTP port", func(c *Config) { c.Server.GRPCPort = c.Server.Port }},
+		{"amadeus environment", func(c *Config) { c.Amadeus.Environment = "staging" }},
+		{"base URL", func(c *Config) { c.Amadeus.BaseURL = "api.amadeus.com" }},
+		{"recorded and replayed", func(c *Config) { c.Amadeus.Record, c.Amadeus.Replay = "a.jsonl", []string{"b.jsonl"} }},
+		{"credentials", func(c *Config) { c.Amadeus.ClientSecret = "" }},
+		{"amadeus max attempts", func(c *Config) { c.Amadeus.MaxAttempts = 0 }},
+		{"duffel max attempts", func(c *Config) {
+			c.Search.Providers, c.Duffel.AccessToken, c.Duffel.MaxAttempts = []string{ProviderDuffel}, "token", 0
+		}},
+		{"at least one flight provider", func(c *Config) { c.Search.Providers = nil }},
+		{"unknown flight provider", func(c *Config) { c.Search.Providers = []string{"sabre"} }},
+		{"listed twice", func(c *Config) { c.Search.Providers = []string{ProviderAmadeus, ProviderAmadeus} }},
+		{"duffel access token", func(c *Config) { c.Search.Providers = []string{ProviderDuffel} }},
+		{"mongo URI is required", func(c *Config) { c.Mongo.URI = "" }},
+		{"no user for the password", func(c *Config) { c.Mongo.URI, c.Mongo.Password = "mongodb://localhost", "pw" }},
+		{"bookings collection", func(c *Config) { c.Mongo.BookingsCollection = "" }},
+		{"ISO 4217", func(c *Config) { c.Search.Currency = "PESOS" }},
+		{"airline", func(c *Config) { c.Search.Airlines = nil }},
+		{"travel class", func(c *Config) { c.Search.TravelClass = "COACH" }},
+		{"owner:key", func(c *Config) { c.Watches.APIKeys = "alice" }},
+		{"another owner's", func(c *Config) { c.Watches.APIKeys = "alice:k1,bob:k1" }},
+		{"per owner", func(c *Config) { c.Watches.MaxPerOwner = 0 }},
+		{"best weights", func(c *Config) { c.Search.BestWeights = "price=lots" }},
+		{"mongo timeout must be positive", func(c *Config) { c.Mongo.Timeout = 0 }},
+		{"rates TTL must be positive", func(c *Config) { c.RatesTTL = -time.Minute }},
+	}
+	for _, tt := range tests {
+		cfg := valid()
+		tt.modify(&cfg)
+		err := cfg.Validate()
+		if !errors.Is(err, ErrInvalid) || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: err = %v", tt.want, err)
+		}
+	}
+
+	// Replayed calls need no credentials.
+	cfg := valid()
+	cfg.Amadeus.ClientID, cfg.Amadeus.ClientSecret, cfg.Amadeus.Replay = "", "", []string{"cassettes"}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("replay without credentials: %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"log/slog"
+	"reflect"
+)
+
+// masked replaces secret values whenever the configuration is printed,
+// logged or encoded.
+const masked = "[REDACTED]"
+
+var secretType = reflect.TypeOf(Secret(""))
+
+// Secret is a configuration value that must not be shown. Formatting,
+// logging and JSON or YAML encoding all mask it; Reveal returns the value.
+type Secret string
+
+// Reveal returns the secret value.
+func (s Secret) Reveal() string {
+	return string(s)
+}
+
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return masked
+}
+
+func (s Secret) GoString() string {
+	return `config.Secret("` + s.String() + `")`
+}
+
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue(s.String())
+}
+
+// MarshalText is used by the JSON and YAML encoders.
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText keeps the decoders from using MarshalText's masked form.
+func (s *Secret) UnmarshalText(text []byte) error {
+	*s = Secret(text)
+	return nil
+}
@@ -0,0 +1,153 @@
+// Package currency converts prices between currencies using a locally stored
+// exchange-rate table. Amadeus always bills in the currency requested by the
+// search, so converted amounts are only referential and are labeled as such.
+package currency
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go-quickstart/money"
+)
+
+var (
+	ErrNoRates   = errors.New("currency: exchange rates not loaded")
+	ErrNoRate    = errors.New("currency: no exchange rate")
+	ErrBadRate   = errors.New("currency: invalid exchange rate")
+	ErrEmptyBase = errors.New("currency: base currency is required")
+)
+
+// Table holds the rates of every known currency against Base, expressed as
+// units of that currency per one unit of Base (e.g. base CLP, "USD": "0.00107").
+type Table struct {
+	Base      string            `json:"base" bson:"base"`
+	Rates     map[string]string `json:"rates" bson:"rates"`
+	UpdatedAt time.Time         `json:"updatedAt" bson:"updatedAt"`
+}
+
+// Validate checks that the table has a base currency and that every rate is
+// a positive decimal number. Currency codes are normalized to upper case.
+func (t *Table) Validate() error {
+	t.Base = strings.ToUpper(strings.TrimSpace(t.Base))
+	if t.Base == "" {
+		return ErrEmptyBase
+	}
+	rates := make(map[string]string, len(t.Rates))
+	for code, rate := range t.Rates {
+		r, ok := new(big.Rat).SetString(rate)
+		if !ok || r.Sign() <= 0 {
+			return fmt.Errorf("%w: %s=%q", ErrBadRate, code, rate)
+		}
+		rates[strings.ToUpper(strings.TrimSpace(code))] = rate
+	}
+	t.Rates = rates
+	return nil
+}
+
+// rate returns the number of units of code per unit of the base currency.
+func (t Table) rate(code string) (*big.Rat, error) {
+	if code == t.Base {
+		return big.NewRat(1, 1), nil
+	}
+	s, ok := t.Rates[code]
+	if !ok {
+		return nil, fmt.Errorf("%w for %s", ErrNoRate, code)
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok || r.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: %s=%q", ErrBadRate, code, s)
+	}
+	return r, nil
+}
+
+// LoadFile reads a JSON rate table from disk, in the same format accepted by
+// the admin endpoint. UpdatedAt defaults to the file modification time.
+func LoadFile(path string) (Table, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Table{}, err
+	}
+	var t Table
+	if err := json.Unmarshal(data, &t); err != nil {
+		return Table{}, fmt.Errorf("currency: decoding %s: %w", path, err)
+	}
+	if err := t.Validate(); err != nil {
+		return Table{}, err
+	}
+	if t.UpdatedAt.IsZero() {
+		if info, err := os.Stat(path); err == nil {
+			t.UpdatedAt = info.ModTime().UTC()
+		}
+	}
+	return t, nil
+}
+
+// Conversion is the result of converting an amount, with enough context to
+// tell the user where the number came from.
+type Conversion struct {
+	Amount         money.Money
+	Rate           *big.Rat // target units per source unit
+	RatesUpdatedAt time.Time
+}
+
+// Service holds the current rate table. It is safe for concurrent use.
+type Service struct {
+	mu       sync.RWMutex
+	table    *Table
+	loadedAt time.Time
+}
+
+// Set replaces the current rate table.
+func (s *Service) Set(t Table) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.table = &t
+	s.loadedAt = time.Now()
+}
+
+// Fresh reports whether a table was set less than ttl ago.
+func (s *Service) Fresh(ttl time.Duration) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.table != nil && time.Since(s.loadedAt) < ttl
+}
+
+// Table returns the current rate table, if one has been loaded.
+func (s *Service) Table() (Table, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.table == nil {
+		return Table{}, false
+	}
+	return *s.table, true
+}
+
+// Convert converts m into the target currency, going through the base
+// currency of the table when neither side is the base.
+func (s *Service) Convert(m money.Money, to string) (Conversion, error) {
+	t, ok := s.Table()
+	if !ok {
+		return Conversion{}, ErrNoRates
+	}
+	to = strings.ToUpper(to)
+	from, err := t.rate(m.Currency())
+	if err != nil {
+		return Conversion{}, err
+	}
+	target, err := t.rate(to)
+	if err != nil {
+		return Conversion{}, err
+	}
+	rate := new(big.Rat).Quo(target, from)
+	return Conversion{
+		Amount:         m.Exchange(rate, to),
+		Rate:           rate,
+		RatesUpdatedAt: t.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,123 @@
+package currency
+
+import (
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-quickstart/money"
+)
+
+func TestConvert(t *testing.T) {
+	updated := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	var s Service
+	s.Set(Table{
+		Base:      "CLP",
+		Rates:     map[string]string{"USD": "0.00107", "EUR": "0.00098", "JPY": "0.16"},
+		UpdatedAt: updated,
+	})
+
+	tests := []struct {
+		name           string
+		amount, from   string
+		to             string
+		want, wantRate string
+	}{
+		{"direct", "250000", "CLP", "USD", "267.50", "0.00107"},
+		{"inverse", "267.50", "USD", "CLP", "250000", "100000/107"},
+		{"through the base", "100", "USD", "EUR", "91.59", "98/107"},
+		{"same currency", "100", "USD", "usd", "100.00", "1"},
+		{"rounds to the minor unit", "12345", "CLP", "EUR", "12.10", "0.00098"},
+		{"rounds half away from zero", "5", "CLP", "USD", "0.01", "0.00107"},
+		{"rounds to zero decimals", "1", "USD", "JPY", "150", "16000/107"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount, err := money.Parse(tt.amount, tt.from)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := s.Convert(amount, tt.to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Amount.Amount() != tt.want {
+				t.Errorf("amount %s, want %s", got.Amount.Amount(), tt.want)
+			}
+			wantRate, _ := new(big.Rat).SetString(tt.wantRate)
+			if got.Rate.Cmp(wantRate) != 0 {
+				t.Errorf("rate %s, want %s", got.Rate.RatString(), wantRate.RatString())
+			}
+			if !got.RatesUpdatedAt.Equal(updated) {
+				t.Errorf("rates updated at %v, want %v", got.RatesUpdatedAt, updated)
+			}
+		})
+	}
+}
+
+func TestConvertErrors(t *testing.T) {
+	var s Service
+	usd, _ := money.Parse("10", "USD")
+	if _, err := s.Convert(usd, "CLP"); !errors.Is(err, ErrNoRates) {
+		t.Errorf("without a table: err = %v, want ErrNoRates", err)
+	}
+
+	s.Set(Table{Base: "CLP", Rates: map[string]string{"USD": "0.00107"}})
+	if _, err := s.Convert(usd, "GBP"); !errors.Is(err, ErrNoRate) {
+		t.Errorf("missing target rate: err = %v, want ErrNoRate", err)
+	}
+	gbp, _ := money.Parse("10", "GBP")
+	if _, err := s.Convert(gbp, "USD"); !errors.Is(err, ErrNoRate) {
+		t.Errorf("missing source rate: err = %v, want ErrNoRate", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	table := Table{Base: " clp ", Rates: map[string]string{"usd ": "0.00107"}}
+	if err := table.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if table.Base != "CLP" || table.Rates["USD"] != "0.00107" {
+		t.Errorf("normalized table %+v", table)
+	}
+	for _, rate := range []string{"0", "-1", "abc", ""} {
+		table := Table{Base: "CLP", Rates: map[string]string{"USD": rate}}
+		if err := table.Validate(); !errors.Is(err, ErrBadRate) {
+			t.Errorf("rate %q: err = %v, want ErrBadRate", rate, err)
+		}
+	}
+	if err := (&Table{}).Validate(); !errors.Is(err, ErrEmptyBase) {
+		t.Errorf("no base: err = %v, want ErrEmptyBase", err)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	if err := os.WriteFile(path, []byte(`{"base":"clp","rates":{"usd":"0.00107"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	table, err := LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.Base != "CLP" || table.Rates["USD"] != "0.00107" || table.UpdatedAt.IsZero() {
+		t.Errorf("loaded %+v, want CLP with USD and the file time", table)
+	}
+}
+
+func TestFresh(t *testing.T) {
+	var s Service
+	if s.Fresh(time.Hour) {
+		t.Error("fresh without a table")
+	}
+	s.Set(Table{Base: "CLP"})
+	if !s.Fresh(time.Hour) {
+		t.Error("not fresh right after Set")
+	}
+	if s.Fresh(0) {
+		t.Error("fresh with a zero TTL")
+	}
+}
@@ -0,0 +1,464 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+	"go-quickstart/config"
+	"go-quickstart/logging"
+	"go-quickstart/upstream"
+)
+
+// duffelClient is shared by every call to Duffel. main applies the
+// configured timeout.
+var duffelClient = &http.Client{
+	Timeout: cfg.Duffel.Timeout,
+	Transport: otelhttp.NewTransport(logging.Transport{},
+		otelhttp.WithSpanNameFormatter(func(_ string, req *http.Request) string {
+			return "duffel " + upstream.Operation(req)
+		})),
+}
+
+// duffel retries Duffel calls as amadeus does Amadeus ones. main applies
+// the configured attempts and breaker cooldown.
+var duffel = &upstream.Client{
+	HTTP:        duffelClient,
+	MaxAttempts: cfg.Duffel.MaxAttempts,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+	Breaker:     upstream.NewBreaker(5, cfg.Duffel.BreakerCooldown),
+	Observe:     observeProvider(config.ProviderDuffel),
+}
+
+// duffelProvider is the Duffel API (https://duffel.com/docs/api/v2). Its
+// offers and orders are translated into the Amadeus documents; the IDs of
+// its offers and of the passengers in them are kept, since pricing and
+// booking need them back.
+type duffelProvider struct{}
+
+// The parts of the Duffel documents the adapter reads.
+type (
+	duffelCode struct {
+		IataCode string `json:"iata_code"`
+	}
+
+	duffelSegment struct {
+		ID                           string            `json:"id"`
+		Origin                       duffelCode        `json:"origin"`
+		Destination                  duffelCode        `json:"destination"`
+		OriginTerminal               string            `json:"origin_terminal"`
+		DestinationTerminal          string            `json:"destination_terminal"`
+		DepartingAt                  string            `json:"departing_at"`
+		ArrivingAt                   string            `json:"arriving_at"`
+		Duration                     string            `json:"duration"`
+		MarketingCarrier             duffelCode        `json:"marketing_carrier"`
+		MarketingCarrierFlightNumber string            `json:"marketing_carrier_flight_number"`
+		OperatingCarrier             duffelCode        `json:"operating_carrier"`
+		Aircraft                     *duffelCode       `json:"aircraft"`
+		Stops                        []json.RawMessage `json:"stops"`
+		Passengers                   []struct {
+			PassengerID   string `json:"passenger_id"`
+			CabinClass    string `json:"cabin_class"`
+			FareBasisCode string `json:"fare_basis_code"`
+			Baggages      []struct {
+				Type     string `json:"type"`
+				Quantity int    `json:"quantity"`
+			} `json:"baggages"`
+		} `json:"passengers"`
+	}
+
+	duffelSlice struct {
+		Duration string          `json:"duration"`
+		Segments []duffelSegment `json:"segments"`
+	}
+
+	duffelOffer struct {
+		ID            string        `json:"id"`
+		TotalAmount   string        `json:"total_amount"`
+		TotalCurrency string        `json:"total_currency"`
+		BaseAmount    string        `json:"base_amount"`
+		ExpiresAt     string        `json:"expires_at"`
+		Owner         duffelCode    `json:"owner"`
+		Slices        []duffelSlice `json:"slices"`
+		Passengers    []struct {
+			ID   string `json:"id"`
+			Type string `json:"type"`
+		} `json:"passengers"`
+	}
+
+	duffelPassenger struct {
+		ID          string `json:"id"`
+		Title       string `json:"title,omitempty"`
+		GivenName   string `json:"given_name"`
+		FamilyName  string `json:"family_name"`
+		BornOn      string `json:"born_on"`
+		Gender      string `json:"gender"`
+		Email       string `json:"email"`
+		PhoneNumber string `json:"phone_number"`
+	}
+
+	duffelOrder struct {
+		ID            string            `json:"id"`
+		TotalAmount   string            `json:"total_amount"`
+		TotalCurrency string            `json:"total_currency"`
+		Slices        []duffelSlice     `json:"slices"`
+		Passengers    []duffelPassenger `json:"passengers"`
+	}
+)
+
+// Search creates an offer request for the configured cabin and airlines.
+func (duffelProvider) Search(ctx context.Context, params searchParams) (FlighOffers, error) {
+	adults, err := strconv.Atoi(params.Adultos)
+	if err != nil || adults < 1 {
+		return FlighOffers{}, invalidInput(fmt.Errorf("adultos must be a positive number, not %q", params.Adultos))
+	}
+	passengers := make([]map[string]string, adults)
+	for i := range passengers {
+		passengers[i] = map[string]string{"type": "adult"}
+	}
+	offerRequest := map[string]any{
+		"slices": []map[string]string{{
+			"origin":         params.Origen,
+			"destination":    params.Destino,
+			"departure_date": params.FechaSalida,
+		}},
+		"passengers":  passengers,
+		"cabin_class": strings.ToLower(cfg.Search.TravelClass),
+	}
+	if cfg.Search.NonStop {
+		offerRequest["max_connections"] = 0
+	}
+	req, err := newDuffelRequest(ctx, "POST", "/air/offer_requests?return_offers=true", offerRequest)
+	if err != nil {
+		return FlighOffers{}, err
+	}
+	var response struct {
+		Offers []duffelOffer `json:"offers"`
+	}
+	// Searching does not change anything upstream, so it is safe to retry.
+	if _, err := duffelDo(upstream.Idempotent(req), "offer-requests.create", &response); err != nil {
+		return FlighOffers{}, err
+	}
+
+	// Duffel has no airline filter, so offers are kept as Amadeus keeps them
+	// with includedAirlineCodes.
+	data := []any{}
+	for _, offer := range response.Offers {
+		included := true
+		for _, slice := range offer.Slices {
+			for _, segment := range slice.Segments {
+				included = included && slices.Contains(cfg.Search.Airlines, segment.MarketingCarrier.IataCode)
+			}
+		}
+		if included {
+			data = append(data, offer.document())
+		}
+	}
+	var offers FlighOffers
+	err = convertDocument(map[string]any{"data": data}, &offers)
+	return offers, err
+}
+
+// Price gets the current price of every offer. Duffel has no fare rules.
+func (duffelProvider) Price(ctx context.Context, searchPrice FlightPriceRequest, _ bool) (PricingResponse, error) {
+	offers := make([]any, len(searchPrice.Data.FlightOffers))
+	for i, searched := range searchPrice.Data.FlightOffers {
+		req, err := newDuffelRequest(ctx, "GET", "/air/offers/"+url.PathEscape(searched.ID), nil)
+		if err != nil {
+			return PricingResponse{}, err
+		}
+		var offer duffelOffer
+		if _, err := duffelDo(req, "offers.get", &offer); err != nil {
+			return PricingResponse{}, err
+		}
+		offers[i] = offer.document()
+	}
+	var pricingResponse PricingResponse
+	err := convertDocument(map[string]any{
+		"data": map[string]any{"type": "flight-offers-pricing", "flightOffers": offers},
+	}, &pricingResponse)
+	return pricingResponse, err
+}
+
+// Book creates an instant order paid from the Duffel balance. Duffel books
+// one offer per order, and its passengers are the travelers in order.
+func (duffelProvider) Book(ctx context.Context, bookingRequest BookingRequest) (BookingResponse, error) {
+	if len(bookingRequest.Data.FlightOffers) != 1 {
+		return BookingResponse{}, invalidInput(errors.New("duffel books one flight offer at a time"))
+	}
+	offer := bookingRequest.Data.FlightOffers[0]
+	travelers := bookingRequest.Data.Travelers
+	if len(travelers) != len(offer.TravelerPricings) {
+		return BookingResponse{}, invalidInput(fmt.Errorf("the offer is for %d travelers, not %d", len(offer.TravelerPricings), len(travelers)))
+	}
+
+	passengers := make([]duffelPassenger, len(travelers))
+	for i, traveler := range travelers {
+		passenger := duffelPassenger{
+			ID:         offer.TravelerPricings[i].TravelerID,
+			GivenName:  traveler.Name.FirstName,
+			FamilyName: traveler.Name.LastName,
+			BornOn:     traveler.DateOfBirth,
+			Email:      traveler.Contact.EmailAddress,
+		}
+		switch traveler.Gender {
+		case "MALE":
+			passenger.Title, passenger.Gender = "mr", "m"
+		case "FEMALE":
+			passenger.Title, passenger.Gender = "ms", "f"
+		case "":
+			return BookingResponse{}, invalidInput(fmt.Errorf("traveler %d: gender is required", i+1))
+		default:
+			return BookingResponse{}, invalidInput(fmt.Errorf("traveler %d: gender must be MALE or FEMALE, not %q", i+1, traveler.Gender))
+		}
+		if phones := traveler.Contact.Phones; len(phones) > 0 {
+			passenger.PhoneNumber = "+" + phones[0].CountryCallingCode + phones[0].Number
+		}
+		passengers[i] = passenger
+	}
+	currency, amount := offer.Price.BillingCurrency, offer.Price.GrandTotal
+	if currency == "" {
+		currency = offer.Price.Currency
+	}
+	if amount == "" {
+		amount = offer.Price.Total
+	}
+
+	req, err := newDuffelRequest(ctx, "POST", "/air/orders", map[string]any{
+		"type":            "instant",
+		"selected_offers": []string{offer.ID},
+		"passengers":      passengers,
+		"payments":        []map[string]string{{"type": "balance", "currency": currency, "amount": amount}},
+	})
+	if err != nil {
+		return BookingResponse{}, err
+	}
+	var order duffelOrder
+	status, err := duffelDo(req, "orders.create", &order)
+	bookingHistory.UpstreamCall(ctx, orderRef(config.ProviderDuffel, order.ID), "orders.create", status, err)
+	if err != nil {
+		return BookingResponse{}, err
+	}
+
+	var bookingResponse BookingResponse
+	bookingResponse.Data.Type = "flight-order"
+	bookingResponse.Data.ID = order.ID
+	return bookingResponse, nil
+}
+
+func (duffelProvider) Order(ctx context.Context, orderID string) (OrderResponse, error) {
+	req, err := newDuffelRequest(ctx, "GET", "/air/orders/"+url.PathEscape(orderID), nil)
+	if err != nil {
+		return OrderResponse{}, err
+	}
+	var order duffelOrder
+	status, err := duffelDo(req, "orders.get", &order)
+	bookingHistory.OrderCall(ctx, orderRef(config.ProviderDuffel, orderID), "orders.get", status, err)
+	if err != nil {
+		return OrderResponse{}, err
+	}
+
+	travelers := make([]any, len(order.Passengers))
+	for i, passenger := range order.Passengers {
+		var gender string
+		switch passenger.Gender {
+		case "m":
+			gender = "MALE"
+		case "f":
+			gender = "FEMALE"
+		}
+		travelers[i] = map[string]any{
+			"id":          passenger.ID,
+			"dateOfBirth": passenger.BornOn,
+			"gender":      gender,
+			"name":        map[string]any{"firstName": passenger.GivenName, "lastName": passenger.FamilyName},
+			"contact": map[string]any{
+				"emailAddress": passenger.Email,
+				// Duffel keeps the number whole, country code included.
+				"phones": []any{map[string]any{"number": strings.TrimPrefix(passenger.PhoneNumber, "+")}},
+			},
+		}
+	}
+	itineraries, _ := duffelItineraries(order.Slices)
+	var orderResponse OrderResponse
+	err = convertDocument(map[string]any{"data": map[string]any{
+		"type":      "flight-order",
+		"id":        order.ID,
+		"travelers": travelers,
+		"flightOffers": []any{map[string]any{
+			"type":        "flight-offer",
+			"id":          order.ID,
+			"itineraries": itineraries,
+			"price":       map[string]any{"currency": order.TotalCurrency, "total": order.TotalAmount, "grandTotal": order.TotalAmount},
+		}},
+	}}, &orderResponse)
+	return orderResponse, err
+}
+
+// Cancel requests the cancellation of an order and confirms it.
+func (duffelProvider) Cancel(ctx context.Context, orderID string) error {
+	ref := orderRef(config.ProviderDuffel, orderID)
+	req, err := newDuffelRequest(ctx, "POST", "/air/order_cancellations", map[string]string{"order_id": orderID})
+	if err != nil {
+		return err
+	}
+	var cancellation struct {
+		ID string `json:"id"`
+	}
+	status, err := duffelDo(req, "order-cancellations.create", &cancellation)
+	bookingHistory.OrderCall(ctx, ref, "order-cancellations.create", status, err)
+	if err != nil {
+		return err
+	}
+
+	req, err = newDuffelRequest(ctx, "POST", "/air/order_cancellations/"+url.PathEscape(cancellation.ID)+"/actions/confirm", nil)
+	if err != nil {
+		return err
+	}
+	status, err = duffelDo(req, "order-cancellations.confirm", nil)
+	bookingHistory.OrderCall(ctx, ref, "order-cancellations.confirm", status, err)
+	return err
+}
+
+// newDuffelRequest returns an authenticated request to Duffel with body,
+// when not nil, as its data member.
+func newDuffelRequest(ctx context.Context, method, path string, body any) (*http.Request, error) {
+	var payload io.Reader
+	if body != nil {
+		data, err := json.Marshal(map[string]any{"data": body})
+		if err != nil {
+			return nil, err
+		}
+		payload = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, cfg.Duffel.URL(path), payload)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+cfg.Duffel.AccessToken.Reveal())
+	req.Header.Set("Duffel-Version", "v2")
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
+
+// duffelDo sends req and decodes the data member of the response into out,
+// when not nil. It returns the response status, 0 if there was none.
+func duffelDo(req *http.Request, operation string, out any) (int, error) {
+	resp, err := duffel.Do(upstream.WithOperation(req, operation))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if err := checkProviderResponse(config.ProviderDuffel, resp); err != nil {
+		return resp.StatusCode, err
+	}
+	if out == nil {
+		return resp.StatusCode, nil
+	}
+	var response struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return resp.StatusCode, fmt.Errorf("decoding duffel %s response: %w", operation, err)
+	}
+	if err := json.Unmarshal(response.Data, out); err != nil {
+		return resp.StatusCode, fmt.Errorf("decoding duffel %s response: %w", operation, err)
+	}
+	return resp.StatusCode, nil
+}
+
+// document is the offer as an Amadeus flight offer. Prices are not split
+// per traveler, as Duffel only gives the total.
+func (o duffelOffer) document() map[string]any {
+	itineraries, fareDetails := duffelItineraries(o.Slices)
+	travelerPricings := make([]any, len(o.Passengers))
+	for i, passenger := range o.Passengers {
+		travelerPricings[i] = map[string]any{
+			"travelerId":           passenger.ID,
+			"fareOption":           "STANDARD",
+			"travelerType":         strings.ToUpper(passenger.Type),
+			"fareDetailsBySegment": fareDetails[passenger.ID],
+		}
+	}
+	lastTicketingDate, _, _ := strings.Cut(o.ExpiresAt, "T")
+	return map[string]any{
+		"type":                   "flight-offer",
+		"id":                     o.ID,
+		"lastTicketingDate":      lastTicketingDate,
+		"itineraries":            itineraries,
+		"price":                  map[string]any{"currency": o.TotalCurrency, "total": o.TotalAmount, "base": o.BaseAmount, "grandTotal": o.TotalAmount},
+		"validatingAirlineCodes": []string{o.Owner.IataCode},
+		"travelerPricings":       travelerPricings,
+	}
+}
+
+// duffelItineraries translates the slices of an offer or order into Amadeus
+// itineraries, and returns the fare details of every segment by passenger.
+func duffelItineraries(duffelSlices []duffelSlice) ([]any, map[string][]any) {
+	itineraries := make([]any, len(duffelSlices))
+	fareDetails := map[string][]any{}
+	for i, slice := range duffelSlices {
+		segments := make([]any, len(slice.Segments))
+		for j, segment := range slice.Segments {
+			operating := segment.OperatingCarrier.IataCode
+			if operating == "" {
+				operating = segment.MarketingCarrier.IataCode
+			}
+			aircraft := ""
+			if segment.Aircraft != nil {
+				aircraft = segment.Aircraft.IataCode
+			}
+			segments[j] = map[string]any{
+				"id":            segment.ID,
+				"departure":     map[string]any{"iataCode": segment.Origin.IataCode, "terminal": segment.OriginTerminal, "at": segment.DepartingAt},
+				"arrival":       map[string]any{"iataCode": segment.Destination.IataCode, "terminal": segment.DestinationTerminal, "at": segment.ArrivingAt},
+				"carrierCode":   segment.MarketingCarrier.IataCode,
+				"number":        segment.MarketingCarrierFlightNumber,
+				"aircraft":      map[string]any{"code": aircraft},
+				"operating":     map[string]any{"carrierCode": operating},
+				"duration":      segment.Duration,
+				"numberOfStops": len(segment.Stops),
+			}
+			for _, passenger := range segment.Passengers {
+				checkedBags := 0
+				for _, bag := range passenger.Baggages {
+					if bag.Type == "checked" {
+						checkedBags += bag.Quantity
+					}
+				}
+				fareDetails[passenger.PassengerID] = append(fareDetails[passenger.PassengerID], map[string]any{
+					"segmentId":           segment.ID,
+					"cabin":               strings.ToUpper(passenger.CabinClass),
+					"fareBasis":           passenger.FareBasisCode,
+					"includedCheckedBags": map[string]any{"quantity": checkedBags},
+				})
+			}
+		}
+		itineraries[i] = map[string]any{"duration": slice.Duration, "segments": segments}
+	}
+	return itineraries, fareDetails
+}
+
+// convertDocument converts doc, built from maps, into v through JSON.
+func convertDocument(doc any, v any) error {
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
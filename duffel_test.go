@@ -0,0 +1,310 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go-quickstart/config"
+	"go-quickstart/upstream"
+)
+
+const fakeDuffelToken = "duffel_test_token"
+
+// duffelOfferFixture is a one-way Duffel offer of a direct flight for the
+// passenger pas_1, priced in CLP.
+func duffelOfferFixture(id, carrier, number, departure, arrival, amount string) string {
+	return fmt.Sprintf(`{
+  "id": %[1]q, "total_amount": %[6]q, "total_currency": "CLP", "base_amount": %[6]q,
+  "expires_at": "2026-11-30T12:00:00Z", "owner": {"iata_code": %[2]q},
+  "passengers": [{"id": "pas_1", "type": "adult"}],
+  "slices": [{"duration": "PT2H40M", "segments": [{
+    "id": "seg_%[1]s", "origin": {"iata_code": "SCL"}, "destination": {"iata_code": "LIM"},
+    "departing_at": %[4]q, "arriving_at": %[5]q, "duration": "PT2H40M",
+    "marketing_carrier": {"iata_code": %[2]q}, "marketing_carrier_flight_number": %[3]q,
+    "operating_carrier": {"iata_code": %[2]q}, "aircraft": {"iata_code": "320"}, "stops": [],
+    "passengers": [{"passenger_id": "pas_1", "cabin_class": "economy", "fare_basis_code": "Y",
+      "baggages": [{"type": "checked", "quantity": 1}]}]
+  }]}]
+}`, id, carrier, number, departure, arrival, amount)
+}
+
+// duffelOffers overlap with searchFixture: off_1 sells offer 1 cheaper,
+// off_2 sells offer 2 dearer, off_3 is only sold by Duffel and off_4 is of
+// an airline that is not searched.
+var duffelOffers = map[string]string{
+	"off_1": duffelOfferFixture("off_1", "LA", "2370", "2026-12-01T08:00:00", "2026-12-01T10:40:00", "230000"),
+	"off_2": duffelSecondOffer,
+	"off_3": duffelOfferFixture("off_3", "H2", "100", "2026-12-01T13:00:00", "2026-12-01T15:40:00", "210000"),
+	"off_4": duffelOfferFixture("off_4", "AA", "950", "2026-12-01T07:00:00", "2026-12-01T09:40:00", "90000"),
+}
+
+// duffelSecondOffer is offer 2 of searchFixture, with its stop in ARI.
+const duffelSecondOffer = `{
+  "id": "off_2", "total_amount": "200000", "total_currency": "CLP", "base_amount": "200000",
+  "expires_at": "2026-11-30T12:00:00Z", "owner": {"iata_code": "JA"},
+  "passengers": [{"id": "pas_1", "type": "adult"}],
+  "slices": [{"duration": "PT6H10M", "segments": [
+    {"id": "seg_a", "origin": {"iata_code": "SCL"}, "destination": {"iata_code": "ARI"},
+     "departing_at": "2026-12-01T06:00:00", "arriving_at": "2026-12-01T08:30:00",
+     "marketing_carrier": {"iata_code": "JA"}, "marketing_carrier_flight_number": "300",
+     "operating_carrier": {"iata_code": "H2"}, "stops": [], "passengers": []},
+    {"id": "seg_b", "origin": {"iata_code": "ARI"}, "destination": {"iata_code": "LIM"},
+     "departing_at": "2026-12-01T09:40:00", "arriving_at": "2026-12-01T12:10:00",
+     "marketing_carrier": {"iata_code": "JA"}, "marketing_carrier_flight_number": "301",
+     "operating_carrier": {"iata_code": "JA"}, "stops": [], "passengers": []}
+  ]}]
+}`
+
+// duffelErrors is an errors document as Duffel returns it.
+func duffelErrors(code, message string) string {
+	return fmt.Sprintf(`{"errors":[{"type":"invalid_request_error","code":%q,"title":%q,"message":%q}]}`, code, message, message)
+}
+
+// fakeDuffel implements the Duffel endpoints duffelProvider calls.
+type fakeDuffel struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	calls    []string
+	failures map[string]int
+	orders   map[string]map[string]any
+}
+
+// withDuffel searches Duffel, served by a new fakeDuffel, besides Amadeus.
+func (s *testServer) withDuffel() *fakeDuffel {
+	f := &fakeDuffel{failures: map[string]int{}, orders: map[string]map[string]any{}}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.serve))
+	s.t.Cleanup(f.Close)
+
+	savedBreaker, savedDelay := duffel.Breaker, duffel.BaseDelay
+	s.t.Cleanup(func() { duffel.Breaker, duffel.BaseDelay = savedBreaker, savedDelay })
+	duffel.Breaker = upstream.NewBreaker(5, time.Minute)
+	duffel.BaseDelay = time.Millisecond
+	cfg.Search.Providers = []string{config.ProviderAmadeus, config.ProviderDuffel}
+	cfg.Duffel.BaseURL = f.URL
+	cfg.Duffel.AccessToken = fakeDuffelToken
+	return f
+}
+
+// fail makes every call to method and path answer status.
+func (f *fakeDuffel) fail(method, path string, status int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures[method+" "+path] = status
+}
+
+// count returns the calls received for method and path.
+func (f *fakeDuffel) count(method, path string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for _, call := range f.calls {
+		if call == method+" "+path {
+			n++
+		}
+	}
+	return n
+}
+
+func (f *fakeDuffel) serve(w http.ResponseWriter, r *http.Request) {
+	key := r.Method + " " + r.URL.Path
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, key)
+
+	reply := func(status int, body string) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		io.WriteString(w, body)
+	}
+	if status, ok := f.failures[key]; ok {
+		reply(status, duffelErrors("internal_server_error", "Something went wrong"))
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer "+fakeDuffelToken || r.Header.Get("Duffel-Version") != "v2" {
+		reply(http.StatusUnauthorized, duffelErrors("unauthorized", "The access token used is not recognised by our system"))
+		return
+	}
+	var request struct {
+		Data map[string]any `json:"data"`
+	}
+	if r.Body != nil {
+		json.NewDecoder(r.Body).Decode(&request)
+	}
+
+	switch {
+	case key == "POST /air/offer_requests":
+		offers := []string{duffelOffers["off_1"], duffelOffers["off_2"], duffelOffers["off_3"], duffelOffers["off_4"]}
+		reply(http.StatusCreated, `{"data":{"id":"orq_1","offers":[`+strings.Join(offers, ",")+`]}}`)
+
+	case r.Method == "GET" && strings.HasPrefix(r.URL.Path, "/air/offers/"):
+		offer, ok := duffelOffers[strings.TrimPrefix(r.URL.Path, "/air/offers/")]
+		if !ok {
+			reply(http.StatusNotFound, duffelErrors("not_found", "The resource you are trying to access does not exist"))
+			return
+		}
+		reply(http.StatusOK, `{"data":`+offer+`}`)
+
+	case key == "POST /air/orders":
+		selected, _ := request.Data["selected_offers"].([]any)
+		passengers, _ := request.Data["passengers"].([]any)
+		if len(selected) != 1 || duffelOffers[fmt.Sprint(selected[0])] == "" || len(passengers) != 1 {
+			reply(http.StatusUnprocessableEntity, duffelErrors("validation_error", "selected_offers or passengers are invalid"))
+			return
+		}
+		if id := passengers[0].(map[string]any)["id"]; id != "pas_1" {
+			reply(http.StatusUnprocessableEntity, duffelErrors("validation_error", fmt.Sprintf("passenger %v is not in the offer", id)))
+			return
+		}
+		var offer map[string]any
+		json.Unmarshal([]byte(duffelOffers[fmt.Sprint(selected[0])]), &offer)
+		order := map[string]any{
+			"id":             fmt.Sprintf("ord_%d", len(f.orders)+1),
+			"total_amount":   offer["total_amount"],
+			"total_currency": offer["total_currency"],
+			"slices":         offer["slices"],
+			"passengers":     passengers,
+		}
+		f.orders[order["id"].(string)] = order
+		body, _ := json.Marshal(map[string]any{"data": order})
+		reply(http.StatusCreated, string(body))
+
+	case r.Method == "GET" && strings.HasPrefix(r.URL.Path, "/air/orders/"):
+		order, ok := f.orders[strings.TrimPrefix(r.URL.Path, "/air/orders/")]
+		if !ok {
+			reply(http.StatusNotFound, duffelErrors("not_found", "The resource you are trying to access does not exist"))
+			return
+		}
+		body, _ := json.Marshal(map[string]any{"data": order})
+		reply(http.StatusOK, string(body))
+
+	case key == "POST /air/order_cancellations":
+		id := fmt.Sprint(request.Data["order_id"])
+		if _, ok := f.orders[id]; !ok {
+			reply(http.StatusNotFound, duffelErrors("not_found", "The resource you are trying to access does not exist"))
+			return
+		}
+		reply(http.StatusCreated, `{"data":{"id":"ore_`+id+`","order_id":"`+id+`"}}`)
+
+	case r.Method == "POST" && strings.HasPrefix(r.URL.Path, "/air/order_cancellations/ore_"):
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/air/order_cancellations/ore_"), "/actions/confirm")
+		delete(f.orders, id)
+		reply(http.StatusOK, `{"data":{"id":"ore_`+id+`","order_id":"`+id+`","confirmed_at":"2026-10-19T12:00:00Z"}}`)
+
+	default:
+		reply(http.StatusNotFound, duffelErrors("not_found", key))
+	}
+}
+
+func TestSearchMergesProviders(t *testing.T) {
+	s := newTestServer(t)
+	duffelAPI := s.withDuffel()
+
+	var offers struct {
+		Meta SearchMeta       `json:"meta"`
+		Data []map[string]any `json:"data"`
+	}
+	if status := s.do("GET", searchURL+"&sort=price", nil, &offers); status != http.StatusCreated {
+		t.Fatalf("search: status %d", status)
+	}
+	var got []string
+	for _, offer := range offers.Data {
+		got = append(got, fmt.Sprintf("%v/%v", offer["provider"], offer["id"]))
+	}
+	want := []string{"amadeus/2", "duffel/off_3", "duffel/off_1"}
+	if strings.Join(got, " ") != strings.Join(want, " ") || offers.Meta.Total != 3 {
+		t.Fatalf("search: got %v of %d, want %v", got, offers.Meta.Total, want)
+	}
+	if tags := offers.Data[0]["tags"]; !strings.Contains(fmt.Sprint(tags), "cheapest") {
+		t.Errorf("search: cheapest offer tagged %v", tags)
+	}
+	offer := offers.Data[2]
+
+	var priced PricingResponse
+	pricing := map[string]any{"data": map[string]any{"type": "flight-offers-pricing", "flightOffers": []any{offer}}}
+	if status := s.do("POST", "/api/pricing", pricing, &priced); status != http.StatusCreated {
+		t.Fatalf("pricing: status %d", status)
+	}
+	if len(priced.Data.FlightOffers) != 1 || priced.Data.FlightOffers[0].Provider != config.ProviderDuffel || priced.Data.FlightOffers[0].Price.GrandTotal != "230000" {
+		t.Fatalf("pricing: got %+v", priced.Data.FlightOffers)
+	}
+	if duffelAPI.count("GET", "/air/offers/off_1") != 1 || s.amadeus.count("POST", "/v1/shopping/flight-offers/pricing") != 0 {
+		t.Error("pricing: the offer was not priced by Duffel alone")
+	}
+
+	var traveler map[string]any
+	json.Unmarshal([]byte(travelerFixture), &traveler)
+	booking := map[string]any{"data": map[string]any{
+		"type":         "flight-order",
+		"flightOffers": []any{priced.Data.FlightOffers[0]},
+		"travelers":    []any{traveler},
+	}}
+	for _, gender := range []any{nil, "UNSPECIFIED"} {
+		traveler["gender"] = gender
+		if status := s.do("POST", "/api/booking", booking, nil); status != http.StatusBadRequest {
+			t.Errorf("booking with gender %v: status %d, want 400", gender, status)
+		}
+	}
+	if duffelAPI.count("POST", "/air/orders") != 0 {
+		t.Error("booking: a traveler without a valid gender was sent to Duffel")
+	}
+	traveler["gender"] = "FEMALE"
+	var booked BookingResponse
+	if status := s.do("POST", "/api/booking", booking, &booked); status != http.StatusCreated {
+		t.Fatalf("booking: status %d", status)
+	}
+	orderID := booked.Data.ID
+	if orderID != "duffel:ord_1" || s.amadeus.count("POST", "/v1/booking/flight-orders") != 0 {
+		t.Fatalf("booking: order %q", orderID)
+	}
+
+	var order OrderResponse
+	if status := s.do("GET", "/api/booking?orderID="+orderID, nil, &order); status != http.StatusCreated {
+		t.Fatalf("order: status %d", status)
+	}
+	if order.Data.ID != orderID || len(order.Data.Travelers) != 1 || order.Data.Travelers[0].Name.LastName != "ROJAS" || order.Data.Travelers[0].Gender != "FEMALE" ||
+		len(order.Data.FlightOffers) != 1 || order.Data.FlightOffers[0].Itineraries[0].Segments[0].Number != "2370" {
+		t.Errorf("order: got %+v", order.Data)
+	}
+
+	if status := s.do("DELETE", "/api/booking/"+orderID, nil, nil); status != http.StatusNoContent {
+		t.Fatalf("cancel: status %d", status)
+	}
+	if duffelAPI.count("POST", "/air/order_cancellations/ore_ord_1/actions/confirm") != 1 {
+		t.Error("cancel: the cancellation was not confirmed")
+	}
+	if status := s.do("GET", "/api/booking?orderID="+orderID, nil, nil); status != http.StatusNotFound {
+		t.Errorf("cancelled order: status %d, want 404", status)
+	}
+
+	mixed := map[string]any{"data": map[string]any{"type": "flight-offers-pricing", "flightOffers": offers.Data[:2]}}
+	if status := s.do("POST", "/api/pricing", mixed, nil); status != http.StatusBadRequest {
+		t.Errorf("pricing offers of two providers: status %d, want 400", status)
+	}
+}
+
+func TestSearchWithoutFailingProvider(t *testing.T) {
+	s := newTestServer(t)
+	duffelAPI := s.withDuffel()
+	duffelAPI.fail("POST", "/air/offer_requests", http.StatusInternalServerError)
+
+	var offers FlighOffers
+	if status := s.do("GET", searchURL, nil, &offers); status != http.StatusCreated {
+		t.Fatalf("search: status %d", status)
+	}
+	if len(offers.Data) != 2 || offers.Data[0].Provider != config.ProviderAmadeus {
+		t.Errorf("search: got %d offers, want the 2 of Amadeus", len(offers.Data))
+	}
+
+	s.amadeus.fail("GET", "/v2/shopping/flight-offers", http.StatusInternalServerError, amadeusErrors(500, 141, "SYSTEM ERROR HAS OCCURRED", ""))
+	if status := s.do("GET", searchURL, nil, nil); status != http.StatusBadGateway {
+		t.Errorf("every provider failing: status %d, want 502", status)
+	}
+}
@@ -0,0 +1,1145 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go-quickstart/config"
+	"go-quickstart/currency"
+	"go-quickstart/health"
+	"go-quickstart/history"
+	"go-quickstart/upstream"
+	"go-quickstart/watch"
+	"go-quickstart/webhook"
+)
+
+// The end-to-end tests run the router against fakeAmadeus and in-memory
+// stores, so they need neither network nor MongoDB.
+
+const (
+	fakeClientID     = "test-client"
+	fakeClientSecret = "test-secret"
+	fakeAccessToken  = "fake-access-token"
+)
+
+// searchFixture has two offers: the first one operated by its marketing
+// carrier, the second one with a codeshare segment and one where Amadeus
+// left the operating carrier out.
+const searchFixture = `{
+  "meta": {"count": 2},
+  "data": [
+    {
+      "type": "flight-offer", "id": "1", "source": "GDS", "oneWay": false,
+      "lastTicketingDate": "2026-11-30", "numberOfBookableSeats": 9,
+      "itineraries": [{"duration": "PT3H40M", "segments": [{
+        "departure": {"iataCode": "SCL", "at": "2026-12-01T08:00:00"},
+        "arrival": {"iataCode": "LIM", "at": "2026-12-01T10:40:00"},
+        "carrierCode": "LA", "number": "2370", "aircraft": {"code": "320"},
+        "operating": {"carrierCode": "LA"}, "duration": "PT3H40M", "id": "1", "numberOfStops": 0
+      }]}],
+      "price": {"currency": "CLP", "total": "250000", "base": "200000", "grandTotal": "250000"},
+      "pricingOptions": {"fareType": ["PUBLISHED"], "includedCheckedBagsOnly": true},
+      "validatingAirlineCodes": ["LA"],
+      "travelerPricings": [{"travelerId": "1", "fareOption": "STANDARD", "travelerType": "ADULT",
+        "price": {"currency": "CLP", "total": "250000", "base": "200000"},
+        "fareDetailsBySegment": [{"segmentId": "1", "cabin": "ECONOMY", "fareBasis": "SLE", "class": "S",
+          "includedCheckedBags": {"quantity": 1}}]}]
+    },
+    {
+      "type": "flight-offer", "id": "2", "source": "GDS", "oneWay": false,
+      "lastTicketingDate": "2026-11-30", "numberOfBookableSeats": 4,
+      "itineraries": [{"duration": "PT6H10M", "segments": [
+        {
+          "departure": {"iataCode": "SCL", "at": "2026-12-01T06:00:00"},
+          "arrival": {"iataCode": "ARI", "at": "2026-12-01T08:30:00"},
+          "carrierCode": "JA", "number": "300", "aircraft": {"code": "320"},
+          "operating": {"carrierCode": "H2"}, "duration": "PT2H30M", "id": "3", "numberOfStops": 0
+        },
+        {
+          "departure": {"iataCode": "ARI", "at": "2026-12-01T09:40:00"},
+          "arrival": {"iataCode": "LIM", "at": "2026-12-01T12:10:00"},
+          "carrierCode": "JA", "number": "301", "aircraft": {"code": "320"},
+          "duration": "PT2H30M", "id": "4", "numberOfStops": 0
+        }
+      ]}],
+      "price": {"currency": "CLP", "total": "180000", "base": "150000", "grandTotal": "180000"},
+      "pricingOptions": {"fareType": ["PUBLISHED"], "includedCheckedBagsOnly": false},
+      "validatingAirlineCodes": ["JA"],
+      "travelerPricings": [{"travelerId": "1", "fareOption": "STANDARD", "travelerType": "ADULT",
+        "price": {"currency": "CLP", "total": "180000", "base": "150000"},
+        "fareDetailsBySegment": [
+          {"segmentId": "3", "cabin": "ECONOMY", "fareBasis": "ZO", "class": "Z"},
+          {"segmentId": "4", "cabin": "ECONOMY", "fareBasis": "ZO", "class": "Z"}
+        ]}]
+    }
+  ],
+  "dictionaries": {}
+}`
+
+const travelerFixture = `{
+  "id": "1", "dateOfBirth": "1990-04-12", "gender": "FEMALE",
+  "name": {"firstName": "ANA", "lastName": "ROJAS"},
+  "contact": {"emailAddress": "ana@example.com",
+    "phones": [{"deviceType": "MOBILE", "countryCallingCode": "56", "number": "912345678"}]}
+}`
+
+// amadeusErrors is an errors document as Amadeus returns it.
+func amadeusErrors(status, code int, title, detail string) string {
+	return fmt.Sprintf(`{"errors":[{"status":%d,"code":%d,"title":%q,"detail":%q}]}`, status, code, title, detail)
+}
+
+// fakeAmadeus implements the Amadeus endpoints the handlers call, with the
+// same authentication and error documents.
+type fakeAmadeus struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	calls    []string
+	failures map[string]func(w http.ResponseWriter)
+	orders   map[string]json.RawMessage
+}
+
+func newFakeAmadeus(t *testing.T) *fakeAmadeus {
+	f := &fakeAmadeus{failures: map[string]func(http.ResponseWriter){}, orders: map[string]json.RawMessage{}}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(f.Close)
+	return f
+}
+
+// fail makes every call to method and path answer status with body.
+func (f *fakeAmadeus) fail(method, path string, status int, body string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures[method+" "+path] = func(w http.ResponseWriter) {
+		w.Header().Set("Content-Type", "application/vnd.amadeus+json")
+		w.WriteHeader(status)
+		io.WriteString(w, body)
+	}
+}
+
+// count returns the calls received for method and path.
+func (f *fakeAmadeus) count(method, path string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for _, call := range f.calls {
+		if call == method+" "+path {
+			n++
+		}
+	}
+	return n
+}
+
+func (f *fakeAmadeus) serve(w http.ResponseWriter, r *http.Request) {
+	key := r.Method + " " + r.URL.Path
+	f.mu.Lock()
+	f.calls = append(f.calls, key)
+	failure := f.failures[key]
+	f.mu.Unlock()
+	if failure != nil {
+		failure(w)
+		return
+	}
+
+	reply := func(status int, body string) {
+		w.Header().Set("Content-Type", "application/vnd.amadeus+json")
+		w.WriteHeader(status)
+		io.WriteString(w, body)
+	}
+	if key == "POST /v1/security/oauth2/token" {
+		r.ParseForm()
+		if r.PostForm.Get("client_id") != fakeClientID || r.PostForm.Get("client_secret") != fakeClientSecret {
+			reply(http.StatusUnauthorized, `{"error":"invalid_client","error_description":"Client credentials are invalid","code":38187,"title":"Invalid parameters"}`)
+			return
+		}
+		reply(http.StatusOK, `{"type":"amadeusOAuth2Token","token_type":"Bearer","access_token":"`+fakeAccessToken+`","expires_in":1799}`)
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer "+fakeAccessToken {
+		reply(http.StatusUnauthorized, amadeusErrors(401, 38190, "Invalid access token", "The access token provided in the Authorization header is invalid"))
+		return
+	}
+
+	switch {
+	case key == "GET /v2/shopping/flight-offers":
+		for _, param := range []string{"originLocationCode", "destinationLocationCode"} {
+			if len(r.URL.Query().Get(param)) != 3 {
+				reply(http.StatusBadRequest, amadeusErrors(400, 477, "INVALID FORMAT", param+" must be a 3-letter IATA code"))
+				return
+			}
+		}
+		if r.URL.Query().Get("departureDate") < "2026-01-01" {
+			reply(http.StatusBadRequest, amadeusErrors(400, 425, "INVALID DATE", "Date/Time is in the past"))
+			return
+		}
+		reply(http.StatusOK, searchFixture)
+
+	case key == "POST /v1/shopping/flight-offers/pricing":
+		var request struct {
+			Data struct {
+				FlightOffers []json.RawMessage `json:"flightOffers"`
+			} `json:"data"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		offers, _ := json.Marshal(request.Data.FlightOffers)
+		reply(http.StatusOK, `{"data":{"type":"flight-offers-pricing","flightOffers":`+string(offers)+`}}`)
+
+	case key == "POST /v1/booking/flight-orders":
+		var request struct {
+			Data struct {
+				FlightOffers []json.RawMessage `json:"flightOffers"`
+				Travelers    []json.RawMessage `json:"travelers"`
+			} `json:"data"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		if len(request.Data.Travelers) == 0 {
+			reply(http.StatusBadRequest, amadeusErrors(400, 477, "INVALID FORMAT", "travelers: at least one traveler is required"))
+			return
+		}
+		f.mu.Lock()
+		id := fmt.Sprintf("eJzTd9f3NjIJdjUwAQALqAJ%d", len(f.orders)+1)
+		offers, _ := json.Marshal(request.Data.FlightOffers)
+		travelers, _ := json.Marshal(request.Data.Travelers)
+		order := `{"type":"flight-order","id":"` + id + `","flightOffers":` + string(offers) + `,"travelers":` + string(travelers) + `}`
+		f.orders[id] = json.RawMessage(order)
+		f.mu.Unlock()
+		reply(http.StatusCreated, `{"data":`+order+`}`)
+
+	case r.Method == "GET" && strings.HasPrefix(r.URL.Path, "/v1/booking/flight-orders/"):
+		id, _ := url.PathUnescape(strings.TrimPrefix(r.URL.Path, "/v1/booking/flight-orders/"))
+		f.mu.Lock()
+		order, ok := f.orders[id]
+		f.mu.Unlock()
+		if !ok {
+			reply(http.StatusNotFound, amadeusErrors(404, 1797, "NOT FOUND", "order not found"))
+			return
+		}
+		reply(http.StatusOK, `{"data":`+string(order)+`}`)
+
+	case r.Method == "DELETE" && strings.HasPrefix(r.URL.Path, "/v1/booking/flight-orders/"):
+		id, _ := url.PathUnescape(strings.TrimPrefix(r.URL.Path, "/v1/booking/flight-orders/"))
+		f.mu.Lock()
+		_, ok := f.orders[id]
+		delete(f.orders, id)
+		f.mu.Unlock()
+		if !ok {
+			reply(http.StatusNotFound, amadeusErrors(404, 1797, "NOT FOUND", "order not found"))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		reply(http.StatusNotFound, amadeusErrors(404, 38196, "Resource not found", key))
+	}
+}
+
+type memoryBookings struct {
+	mu   sync.Mutex
+	list []BookingResponse
+}
+
+func (m *memoryBookings) Insert(_ context.Context, booking BookingResponse) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.list = append(m.list, booking)
+	return nil
+}
+
+type memoryHistory struct {
+	mu     sync.Mutex
+	events []history.Event
+}
+
+func (m *memoryHistory) Append(_ context.Context, e history.Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, e)
+	return nil
+}
+
+func (m *memoryHistory) Timeline(_ context.Context, orderID string) ([]history.Event, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var events []history.Event
+	for _, e := range m.events {
+		if e.OrderID == orderID {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+// memoryWatches keeps price watches.
+type memoryWatches struct {
+	mu   sync.Mutex
+	list []watch.Watch
+}
+
+func (m *memoryWatches) Insert(_ context.Context, w watch.Watch) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.list = append(m.list, w)
+	return nil
+}
+
+func (m *memoryWatches) List(context.Context) ([]watch.Watch, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]watch.Watch{}, m.list...), nil
+}
+
+func (m *memoryWatches) Owned(_ context.Context, owner string) ([]watch.Watch, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	owned := []watch.Watch{}
+	for _, w := range m.list {
+		if w.Owner == owner {
+			owned = append(owned, w)
+		}
+	}
+	return owned, nil
+}
+
+func (m *memoryWatches) Update(_ context.Context, w watch.Watch) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range m.list {
+		if m.list[i].ID == w.ID {
+			m.list[i] = w
+		}
+	}
+	return nil
+}
+
+func (m *memoryWatches) Delete(_ context.Context, id, owner string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, w := range m.list {
+		if w.ID == id && w.Owner == owner {
+			m.list = append(m.list[:i], m.list[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// memoryRates keeps exchange-rate tables; err, when set, fails every call.
+type memoryRates struct {
+	mu     sync.Mutex
+	tables []currency.Table
+	err    error
+}
+
+func (m *memoryRates) Save(_ context.Context, table currency.Table) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	m.tables = append(m.tables, table)
+	return nil
+}
+
+func (m *memoryRates) Latest(context.Context) (currency.Table, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return currency.Table{}, m.err
+	}
+	if len(m.tables) == 0 {
+		return currency.Table{}, currency.ErrNoRates
+	}
+	return m.tables[len(m.tables)-1], nil
+}
+
+// memoryWebhooks keeps webhook subscriptions and deliveries.
+type memoryWebhooks struct {
+	mu            sync.Mutex
+	subscriptions []webhook.Subscription
+	deliveries    map[string]webhook.Delivery
+}
+
+func (m *memoryWebhooks) InsertSubscription(_ context.Context, sub webhook.Subscription) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.subscriptions = append(m.subscriptions, sub)
+	return nil
+}
+
+func (m *memoryWebhooks) DeleteSubscription(_ context.Context, id string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, sub := range m.subscriptions {
+		if sub.ID == id {
+			m.subscriptions = append(m.subscriptions[:i], m.subscriptions[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *memoryWebhooks) Subscriptions(context.Context) ([]webhook.Subscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]webhook.Subscription{}, m.subscriptions...), nil
+}
+
+func (m *memoryWebhooks) Subscription(_ context.Context, id string) (webhook.Subscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, sub := range m.subscriptions {
+		if sub.ID == id {
+			return sub, nil
+		}
+	}
+	return webhook.Subscription{}, webhook.ErrNotFound
+}
+
+func (m *memoryWebhooks) SaveDelivery(_ context.Context, d webhook.Delivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deliveries[d.ID] = d
+	return nil
+}
+
+func (m *memoryWebhooks) Delivery(_ context.Context, id string) (webhook.Delivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	d, ok := m.deliveries[id]
+	if !ok {
+		return webhook.Delivery{}, webhook.ErrNotFound
+	}
+	return d, nil
+}
+
+func (m *memoryWebhooks) PendingDeliveries(context.Context) ([]webhook.Delivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var pending []webhook.Delivery
+	for _, d := range m.deliveries {
+		if d.Status == webhook.StatusPending {
+			pending = append(pending, d)
+		}
+	}
+	return pending, nil
+}
+
+func (m *memoryWebhooks) Deliveries(_ context.Context, limit int64) ([]webhook.Delivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	deliveries := []webhook.Delivery{}
+	for _, d := range m.deliveries {
+		deliveries = append(deliveries, d)
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt) })
+	return deliveries[:min(int64(len(deliveries)), limit)], nil
+}
+
+// testServer points the server at a new fakeAmadeus and in-memory stores,
+// restoring the globals when the test ends.
+type testServer struct {
+	t        *testing.T
+	handler  http.Handler
+	amadeus  *fakeAmadeus
+	bookings *memoryBookings
+	rates    *memoryRates
+	webhooks *memoryWebhooks
+	// token, when set, is sent as the bearer token.
+	token string
+}
+
+// as returns a copy of s that sends token as the bearer token.
+func (s *testServer) as(token string) *testServer {
+	copied := *s
+	copied.token = token
+	return &copied
+}
+
+const testAdminToken = "admin-token"
+
+func newTestServer(t *testing.T) *testServer {
+	fake := newFakeAmadeus(t)
+	store := &memoryBookings{}
+	rateStore := &memoryRates{}
+	webhookStore := &memoryWebhooks{deliveries: map[string]webhook.Delivery{}}
+
+	savedCfg, savedBookings, savedHistory, savedWebhooks := cfg, bookings, bookingHistory, webhooks.Store
+	savedRates, savedWatches := rateTables, watches
+	savedBreaker, savedDelay, savedLogger := amadeus.Breaker, amadeus.BaseDelay, slog.Default()
+	t.Cleanup(func() {
+		background.Wait()
+		cfg, bookings, bookingHistory, webhooks.Store = savedCfg, savedBookings, savedHistory, savedWebhooks
+		rateTables, rates, watches = savedRates, currency.Service{}, savedWatches
+		amadeus.Breaker, amadeus.BaseDelay = savedBreaker, savedDelay
+		slog.SetDefault(savedLogger)
+	})
+
+	cfg = config.Default()
+	cfg.Amadeus.BaseURL = fake.URL
+	cfg.Amadeus.ClientID = fakeClientID
+	cfg.Amadeus.ClientSecret = fakeClientSecret
+	cfg.AdminToken = testAdminToken
+	bookings = store
+	bookingHistory = history.Recorder{Store: &memoryHistory{}}
+	rateTables, rates, watches = rateStore, currency.Service{}, &memoryWatches{}
+	webhooks.Store = webhookStore
+	amadeus.Breaker = upstream.NewBreaker(5, time.Minute)
+	amadeus.BaseDelay = time.Millisecond
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	return &testServer{t: t, handler: setupRouter(), amadeus: fake, bookings: store, rates: rateStore, webhooks: webhookStore}
+}
+
+// do sends a request to the router and decodes the JSON response into out,
+// when not nil. It returns the status code.
+func (s *testServer) do(method, target string, body any, out any) int {
+	s.t.Helper()
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			s.t.Fatal(err)
+		}
+		reader = bytes.NewReader(raw)
+	}
+	req := httptest.NewRequest(method, target, reader)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+	rec := httptest.NewRecorder()
+	s.handler.ServeHTTP(rec, req)
+	if out != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			s.t.Fatalf("%s %s: decoding %q: %v", method, target, rec.Body.String(), err)
+		}
+	}
+	return rec.Code
+}
+
+const searchURL = "/api/search?origen=SCL&destino=LIM&fecha=2026-12-01&adultos=1"
+
+func TestSearchPriceBookAndRetrieve(t *testing.T) {
+	s := newTestServer(t)
+
+	var offers struct {
+		Meta SearchMeta       `json:"meta"`
+		Data []map[string]any `json:"data"`
+	}
+	if status := s.do("GET", searchURL, nil, &offers); status != http.StatusCreated {
+		t.Fatalf("search: status %d", status)
+	}
+	if offers.Meta.Total != 2 || len(offers.Data) != 2 {
+		t.Fatalf("search: got %d of %d offers, want 2", len(offers.Data), offers.Meta.Total)
+	}
+	offer := offers.Data[0]
+
+	var priced PricingResponse
+	pricing := map[string]any{"data": map[string]any{"type": "flight-offers-pricing", "flightOffers": []any{offer}}}
+	if status := s.do("POST", "/api/pricing", pricing, &priced); status != http.StatusCreated {
+		t.Fatalf("pricing: status %d", status)
+	}
+	if len(priced.Data.FlightOffers) != 1 || priced.Data.FlightOffers[0].ID != offer["id"] {
+		t.Fatalf("pricing: got %+v, want offer %v", priced.Data.FlightOffers, offer["id"])
+	}
+
+	var traveler map[string]any
+	json.Unmarshal([]byte(travelerFixture), &traveler)
+	booking := map[string]any{"data": map[string]any{
+		"type":         "flight-order",
+		"flightOffers": []any{offer},
+		"travelers":    []any{traveler},
+	}}
+	var booked BookingResponse
+	if status := s.do("POST", "/api/booking", booking, &booked); status != http.StatusCreated {
+		t.Fatalf("booking: status %d", status)
+	}
+	orderID := booked.Data.ID
+	if orderID == "" {
+		t.Fatal("booking: no order ID")
+	}
+	if len(s.bookings.list) != 1 || s.bookings.list[0].Data.ID != orderID {
+		t.Errorf("booking: saved %+v, want order %s", s.bookings.list, orderID)
+	}
+
+	var order OrderResponse
+	if status := s.do("GET", "/api/booking?orderID="+orderID, nil, &order); status != http.StatusCreated {
+		t.Fatalf("order: status %d", status)
+	}
+	if order.Data.ID != orderID || len(order.Data.Travelers) != 1 || order.Data.Travelers[0].Name.LastName != "ROJAS" {
+		t.Errorf("order: got %+v", order.Data)
+	}
+
+	if status := s.do("GET", "/api/booking/"+orderID+"/events", nil, nil); status != http.StatusUnauthorized {
+		t.Errorf("events without the admin token: status %d, want 401", status)
+	}
+	var timeline history.Timeline
+	if status := s.as(testAdminToken).do("GET", "/api/booking/"+orderID+"/events", nil, &timeline); status != http.StatusOK {
+		t.Fatalf("events: status %d", status)
+	}
+	if timeline.Status != history.TypeCreated {
+		t.Errorf("events: status %q, want %q", timeline.Status, history.TypeCreated)
+	}
+	if last := timeline.Events[len(timeline.Events)-1]; last.Operation != "flight-orders.get" {
+		t.Errorf("events: last is %+v, want the retrieval of the order", last)
+	}
+}
+
+func TestSearchFillsOperatingCarrier(t *testing.T) {
+	s := newTestServer(t)
+
+	var offers FlighOffers
+	if status := s.do("GET", searchURL+"&sort=price", nil, &offers); status != http.StatusCreated {
+		t.Fatalf("status %d", status)
+	}
+	got := map[string]string{}
+	for _, offer := range offers.Data {
+		for _, itinerary := range offer.Itineraries {
+			for _, segment := range itinerary.Segments {
+				got[segment.ID] = segment.CarrierCode + "/" + segment.Operating.CarrierCode
+			}
+		}
+	}
+	want := map[string]string{
+		"1": "LA/LA", // operated by the marketing carrier
+		"3": "JA/H2", // codeshare, kept as returned
+		"4": "JA/JA", // missing, filled with the marketing carrier
+	}
+	for id, carriers := range want {
+		if got[id] != carriers {
+			t.Errorf("segment %s: carrier/operating %q, want %q", id, got[id], carriers)
+		}
+	}
+}
+
+func TestTokenFailure(t *testing.T) {
+	s := newTestServer(t)
+	cfg.Amadeus.ClientSecret = "revoked"
+
+	var response ErrorResponse
+	if status := s.do("GET", searchURL, nil, &response); status != http.StatusBadGateway {
+		t.Fatalf("status %d, want %d", status, http.StatusBadGateway)
+	}
+	if !strings.Contains(string(response.Details), "Invalid access token") {
+		t.Errorf("details %s do not carry the Amadeus errors", response.Details)
+	}
+	if strings.Contains(response.Error, "revoked") || strings.Contains(string(response.Details), "revoked") {
+		t.Errorf("response leaks the client secret: %+v", response)
+	}
+}
+
+func TestUpstreamValidationError(t *testing.T) {
+	s := newTestServer(t)
+
+	var response ErrorResponse
+	status := s.do("GET", "/api/search?origen=SANTIAGO&destino=LIM&fecha=2026-12-01&adultos=1", nil, &response)
+	if status != http.StatusBadRequest {
+		t.Fatalf("search: status %d, want %d", status, http.StatusBadRequest)
+	}
+	if !strings.Contains(string(response.Details), "originLocationCode") {
+		t.Errorf("search: details %s do not name the rejected parameter", response.Details)
+	}
+
+	// Parameters are escaped, so one cannot smuggle in another.
+	response = ErrorResponse{}
+	status = s.do("GET", "/api/search?origen=SCL%26destinationLocationCode%3DLIM&destino=XX&fecha=2026-12-01&adultos=1", nil, &response)
+	if status != http.StatusBadRequest || !strings.Contains(string(response.Details), "originLocationCode") {
+		t.Errorf("injected parameter: status %d, details %s", status, response.Details)
+	}
+
+	var offers FlighOffers
+	s.do("GET", searchURL, nil, &offers)
+	booking := map[string]any{"data": map[string]any{"type": "flight-order", "flightOffers": offers.Data[:1]}}
+	response = ErrorResponse{}
+	if status := s.do("POST", "/api/booking", booking, &response); status != http.StatusBadRequest {
+		t.Fatalf("booking: status %d, want %d", status, http.StatusBadRequest)
+	}
+	if !strings.Contains(string(response.Details), "travelers") {
+		t.Errorf("booking: details %s do not carry the Amadeus errors", response.Details)
+	}
+	if len(s.bookings.list) != 0 {
+		t.Errorf("booking: rejected order was saved: %+v", s.bookings.list)
+	}
+}
+
+func TestUpstreamServerError(t *testing.T) {
+	s := newTestServer(t)
+	internal := amadeusErrors(500, 141, "SYSTEM ERROR HAS OCCURRED", "")
+	s.amadeus.fail("GET", "/v2/shopping/flight-offers", http.StatusInternalServerError, internal)
+	s.amadeus.fail("POST", "/v1/booking/flight-orders", http.StatusInternalServerError, internal)
+
+	var response ErrorResponse
+	if status := s.do("GET", searchURL, nil, &response); status != http.StatusBadGateway {
+		t.Fatalf("search: status %d, want %d", status, http.StatusBadGateway)
+	}
+	if !strings.Contains(string(response.Details), "SYSTEM ERROR") {
+		t.Errorf("search: details %s do not carry the Amadeus errors", response.Details)
+	}
+	// Searches are idempotent and retried; bookings are not.
+	if n := s.amadeus.count("GET", "/v2/shopping/flight-offers"); n != amadeus.MaxAttempts {
+		t.Errorf("search: %d attempts, want %d", n, amadeus.MaxAttempts)
+	}
+
+	var traveler map[string]any
+	json.Unmarshal([]byte(travelerFixture), &traveler)
+	booking := map[string]any{"data": map[string]any{"type": "flight-order", "travelers": []any{traveler}}}
+	if status := s.do("POST", "/api/booking", booking, nil); status != http.StatusBadGateway {
+		t.Fatalf("booking: status %d, want %d", status, http.StatusBadGateway)
+	}
+	if n := s.amadeus.count("POST", "/v1/booking/flight-orders"); n != 1 {
+		t.Errorf("booking: %d attempts, want 1", n)
+	}
+	if events, _ := bookingHistory.Store.Timeline(context.Background(), ""); len(events) != 0 {
+		t.Errorf("booking: failed create recorded without an order ID: %+v", events)
+	}
+}
+
+func TestOrderNotFound(t *testing.T) {
+	s := newTestServer(t)
+
+	var response ErrorResponse
+	if status := s.do("GET", "/api/booking?orderID=missing", nil, &response); status != http.StatusNotFound {
+		t.Fatalf("status %d, want %d", status, http.StatusNotFound)
+	}
+	if !strings.Contains(string(response.Details), "NOT FOUND") {
+		t.Errorf("details %s do not carry the Amadeus errors", response.Details)
+	}
+
+	// Looking up a made-up order must not give it a timeline.
+	s.do("GET", "/api/booking/missing/ics", nil, nil)
+	s.do("DELETE", "/api/booking/missing", nil, nil)
+	if status := s.as(testAdminToken).do("GET", "/api/booking/missing/events", nil, nil); status != http.StatusNotFound {
+		t.Errorf("events: status %d, want 404", status)
+	}
+}
+
+func TestDisplayCurrency(t *testing.T) {
+	s := newTestServer(t)
+	s.rates.Save(context.Background(), currency.Table{Base: "CLP", Rates: map[string]string{"USD": "0.001"}})
+
+	var offers FlighOffers
+	if status := s.do("GET", searchURL+"&moneda=USD", nil, &offers); status != http.StatusCreated {
+		t.Fatalf("status %d, want %d", status, http.StatusCreated)
+	}
+	want := map[string]string{"250000": "250.00", "180000": "180.00"}
+	for _, offer := range offers.Data {
+		got := offer.DisplayPrice
+		if got == nil || got.Currency != "USD" || got.Total != want[offer.Price.Total] {
+			t.Errorf("offer %s: display price %+v, want %s USD", offer.ID, got, want[offer.Price.Total])
+		}
+	}
+
+	// A currency without a rate is the client's mistake.
+	var response ErrorResponse
+	if status := s.do("GET", searchURL+"&moneda=XYZ", nil, &response); status != http.StatusBadRequest {
+		t.Fatalf("unknown currency: status %d, want %d", status, http.StatusBadRequest)
+	}
+	if !strings.Contains(response.Error, "XYZ") {
+		t.Errorf("unknown currency: error %q does not name the currency", response.Error)
+	}
+}
+
+func TestRatesReload(t *testing.T) {
+	s := newTestServer(t)
+	usdTotal := func() string {
+		t.Helper()
+		var offers FlighOffers
+		if status := s.do("GET", searchURL+"&moneda=USD&sort=price", nil, &offers); status != http.StatusCreated {
+			t.Fatalf("status %d, want %d", status, http.StatusCreated)
+		}
+		return offers.Data[0].DisplayPrice.Total
+	}
+
+	// Without any table the server, not the client, has to be fixed.
+	if status := s.do("GET", searchURL+"&moneda=USD", nil, nil); status != http.StatusServiceUnavailable {
+		t.Fatalf("no rates: status %d, want %d", status, http.StatusServiceUnavailable)
+	}
+
+	table := currency.Table{Base: "CLP", Rates: map[string]string{"USD": "0.001"}}
+	if err := storeRates(context.Background(), table); err != nil {
+		t.Fatal(err)
+	}
+	if got := usdTotal(); got != "180.00" {
+		t.Errorf("stored table: total %s, want 180.00", got)
+	}
+
+	// A table saved by another instance is used once the TTL expires.
+	s.rates.Save(context.Background(), currency.Table{Base: "CLP", Rates: map[string]string{"USD": "0.002"}})
+	if got := usdTotal(); got != "180.00" {
+		t.Errorf("before the TTL: total %s, want 180.00", got)
+	}
+	cfg.RatesTTL = 0
+	if got := usdTotal(); got != "360.00" {
+		t.Errorf("after the TTL: total %s, want 360.00", got)
+	}
+
+	// A failed reload keeps the table in use.
+	s.rates.mu.Lock()
+	s.rates.err = errors.New("mongo is down")
+	s.rates.mu.Unlock()
+	if got := usdTotal(); got != "360.00" {
+		t.Errorf("failed reload: total %s, want 360.00", got)
+	}
+}
+
+func TestAdminWebhooks(t *testing.T) {
+	s := newTestServer(t)
+	admin := s.as(testAdminToken)
+	received := make(chan string, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Get("X-GoTravel-Delivery")
+	}))
+	defer receiver.Close()
+
+	sub := map[string]any{"url": receiver.URL, "events": []string{webhook.EventBookingCreated}}
+	if status := s.do("POST", "/api/admin/webhooks", sub, nil); status != http.StatusUnauthorized {
+		t.Fatalf("without the admin token: status %d, want %d", status, http.StatusUnauthorized)
+	}
+	if status := admin.do("POST", "/api/admin/webhooks", map[string]any{"url": "ftp://example.com"}, nil); status != http.StatusBadRequest {
+		t.Errorf("invalid subscription: status %d, want %d", status, http.StatusBadRequest)
+	}
+	var created webhook.Subscription
+	if status := admin.do("POST", "/api/admin/webhooks", sub, &created); status != http.StatusCreated {
+		t.Fatalf("create: status %d, want %d", status, http.StatusCreated)
+	}
+	if created.ID == "" || created.Secret == "" {
+		t.Errorf("created subscription %+v has no ID or secret", created)
+	}
+
+	var listed []webhook.Subscription
+	if status := admin.do("GET", "/api/admin/webhooks", nil, &listed); status != http.StatusOK {
+		t.Fatalf("list: status %d", status)
+	}
+	if len(listed) != 1 || listed[0].ID != created.ID || listed[0].Secret != "" {
+		t.Errorf("listed %+v, want the subscription without its secret", listed)
+	}
+
+	failed := webhook.Delivery{
+		ID:             "delivery-1",
+		SubscriptionID: created.ID,
+		EventType:      webhook.EventBookingCreated,
+		URL:            receiver.URL,
+		Payload:        `{}`,
+		Status:         webhook.StatusFailed,
+		Attempts:       6,
+		CreatedAt:      time.Now().UTC(),
+	}
+	s.webhooks.SaveDelivery(context.Background(), failed)
+	var deliveries []webhook.Delivery
+	if status := admin.do("GET", "/api/admin/webhooks/deliveries?limit=10", nil, &deliveries); status != http.StatusOK {
+		t.Fatalf("deliveries: status %d", status)
+	}
+	if len(deliveries) != 1 || deliveries[0].ID != failed.ID {
+		t.Errorf("deliveries %+v, want %s", deliveries, failed.ID)
+	}
+	if status := admin.do("GET", "/api/admin/webhooks/deliveries?limit=0", nil, nil); status != http.StatusBadRequest {
+		t.Errorf("limit=0: status %d, want %d", status, http.StatusBadRequest)
+	}
+
+	var replayed webhook.Delivery
+	if status := admin.do("POST", "/api/admin/webhooks/deliveries/"+failed.ID+"/replay", nil, &replayed); status != http.StatusOK {
+		t.Fatalf("replay: status %d", status)
+	}
+	if replayed.Status != webhook.StatusDelivered || replayed.Attempts != 7 {
+		t.Errorf("replayed delivery is %s after %d attempts", replayed.Status, replayed.Attempts)
+	}
+	select {
+	case id := <-received:
+		if id != failed.ID {
+			t.Errorf("receiver got delivery %q, want %q", id, failed.ID)
+		}
+	default:
+		t.Error("the replay did not reach the receiver")
+	}
+	if status := admin.do("POST", "/api/admin/webhooks/deliveries/unknown/replay", nil, nil); status != http.StatusNotFound {
+		t.Errorf("replaying an unknown delivery: status %d, want %d", status, http.StatusNotFound)
+	}
+
+	if status := admin.do("DELETE", "/api/admin/webhooks/"+created.ID, nil, nil); status != http.StatusNoContent {
+		t.Errorf("delete: status %d, want %d", status, http.StatusNoContent)
+	}
+	if status := admin.do("DELETE", "/api/admin/webhooks/"+created.ID, nil, nil); status != http.StatusNotFound {
+		t.Errorf("deleting again: status %d, want %d", status, http.StatusNotFound)
+	}
+	if admin.do("GET", "/api/admin/webhooks", nil, &listed); len(listed) != 0 {
+		t.Errorf("subscriptions after the delete: %+v", listed)
+	}
+}
+
+func TestHealthEndpoints(t *testing.T) {
+	s := newTestServer(t)
+	saved := readiness
+	t.Cleanup(func() { readiness = saved })
+	var mongoErr error
+	newChecker := func() *health.Checker {
+		return &health.Checker{Checks: []health.Check{
+			{Name: "mongo", Func: func(context.Context) error { return mongoErr }},
+			{Name: "amadeusToken", Func: checkToken},
+			{Name: "amadeus", Func: checkAmadeus},
+		}, TTL: time.Hour}
+	}
+	readyz := func(want int) health.Report {
+		t.Helper()
+		var report health.Report
+		if status := s.do("GET", "/readyz", nil, &report); status != want {
+			t.Fatalf("readyz: status %d, want %d: %+v", status, want, report)
+		}
+		return report
+	}
+
+	readiness = newChecker()
+	report := readyz(http.StatusOK)
+	for _, name := range []string{"mongo", "amadeusToken", "amadeus"} {
+		if report.Checks[name].Status != health.StatusOK {
+			t.Errorf("%s: %+v", name, report.Checks[name])
+		}
+	}
+	readyz(http.StatusOK)
+	if n := s.amadeus.count("POST", "/v1/security/oauth2/token"); n != 1 {
+		t.Errorf("token requested %d times, want 1 for a cached report", n)
+	}
+
+	mongoErr = errors.New("server selection timeout")
+	s.amadeus.fail("POST", "/v1/security/oauth2/token", http.StatusUnauthorized,
+		amadeusErrors(401, 38190, "Invalid access token", ""))
+	readiness = newChecker()
+	report = readyz(http.StatusServiceUnavailable)
+	if got := report.Checks["mongo"]; got.Status != health.StatusUnavailable || got.Error != mongoErr.Error() {
+		t.Errorf("mongo: %+v", got)
+	}
+	if got := report.Checks["amadeusToken"]; got.Status != health.StatusUnavailable || got.Error == "" {
+		t.Errorf("amadeusToken: %+v", got)
+	}
+	// A 401 still shows the API is reachable.
+	if got := report.Checks["amadeus"]; got.Status != health.StatusOK {
+		t.Errorf("amadeus: %+v", got)
+	}
+
+	readiness.Drain()
+	if report := readyz(http.StatusServiceUnavailable); report.Checks["server"].Status != health.StatusUnavailable {
+		t.Errorf("draining: %+v", report)
+	}
+	var alive map[string]string
+	if status := s.do("GET", "/healthz", nil, &alive); status != http.StatusOK || alive["status"] != health.StatusOK {
+		t.Errorf("healthz while draining: status %d, %v", status, alive)
+	}
+}
+
+func TestWatchesBelongToTheirOwner(t *testing.T) {
+	s := newTestServer(t)
+	cfg.Watches.APIKeys = "alice:alice-key,bob:bob-key"
+	cfg.Watches.MaxPerOwner = 2
+	alice, bob := s.as("alice-key"), s.as("bob-key")
+	newWatch := func(notifyURL string) map[string]any {
+		return map[string]any{
+			"origin": "SCL", "destination": "LIM", "departureDate": time.Now().AddDate(0, 1, 0).Format("2006-01-02"),
+			"adults": 1, "threshold": "150000", "notifyURL": notifyURL,
+		}
+	}
+
+	if status := s.do("GET", "/api/watches", nil, nil); status != http.StatusUnauthorized {
+		t.Errorf("without a key: status %d, want %d", status, http.StatusUnauthorized)
+	}
+	if status := s.as("eve-key").do("POST", "/api/watches", newWatch(""), nil); status != http.StatusUnauthorized {
+		t.Errorf("unknown key: status %d, want %d", status, http.StatusUnauthorized)
+	}
+
+	var created watch.Watch
+	if status := alice.do("POST", "/api/watches", newWatch("https://hooks.example.com/alerts"), &created); status != http.StatusCreated {
+		t.Fatalf("create: status %d, want %d", status, http.StatusCreated)
+	}
+	if created.Owner != "alice" {
+		t.Errorf("create: owner %q, want alice", created.Owner)
+	}
+
+	for _, notifyURL := range []string{
+		"http://hooks.example.com/alerts",
+		"https://127.0.0.1/alerts",
+		"https://localhost:8080/alerts",
+		"https://10.1.2.3/alerts",
+		"https://169.254.169.254/latest/meta-data",
+		"https://[::1]/alerts",
+	} {
+		if status := alice.do("POST", "/api/watches", newWatch(notifyURL), nil); status != http.StatusBadRequest {
+			t.Errorf("notifyURL %s: status %d, want %d", notifyURL, status, http.StatusBadRequest)
+		}
+	}
+
+	var list []watch.Watch
+	bob.do("GET", "/api/watches", nil, &list)
+	if len(list) != 0 {
+		t.Errorf("bob sees %d of alice's watches", len(list))
+	}
+	if status := bob.do("DELETE", "/api/watches/"+created.ID, nil, nil); status != http.StatusNotFound {
+		t.Errorf("bob deletes alice's watch: status %d, want %d", status, http.StatusNotFound)
+	}
+
+	if status := alice.do("POST", "/api/watches", newWatch(""), nil); status != http.StatusCreated {
+		t.Fatalf("second watch: status %d, want %d", status, http.StatusCreated)
+	}
+	if status := alice.do("POST", "/api/watches", newWatch(""), nil); status != http.StatusConflict {
+		t.Errorf("over the limit: status %d, want %d", status, http.StatusConflict)
+	}
+	alice.do("GET", "/api/watches", nil, &list)
+	if len(list) != 2 {
+		t.Errorf("alice has %d watches, want 2", len(list))
+	}
+	if status := alice.do("DELETE", "/api/watches/"+created.ID, nil, nil); status != http.StatusNoContent {
+		t.Errorf("delete: status %d, want %d", status, http.StatusNoContent)
+	}
+}
+
+type streamEvent struct {
+	Name string
+	Data map[string]any
+}
+
+// readEvents parses a text/event-stream body.
+func readEvents(t *testing.T, body string) []streamEvent {
+	t.Helper()
+	var events []streamEvent
+	for _, block := range strings.Split(strings.TrimSpace(body), "\n\n") {
+		var event streamEvent
+		for _, line := range strings.Split(block, "\n") {
+			field, value, _ := strings.Cut(line, ":")
+			switch field {
+			case "event":
+				event.Name = value
+			case "data":
+				if err := json.Unmarshal([]byte(value), &event.Data); err != nil {
+					t.Fatalf("event %s: %v", event.Name, err)
+				}
+			}
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestSearchStream(t *testing.T) {
+	s := newTestServer(t)
+
+	req := httptest.NewRequest("GET", "/api/search/stream?origen=SCL&destino=LIM&fecha=2026-12-01,2025-01-01,2026-12-02&adultos=1&sort=price", nil)
+	rec := httptest.NewRecorder()
+	s.handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/event-stream") {
+		t.Fatalf("status %d, content type %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	events := readEvents(t, rec.Body.String())
+
+	if first := events[0]; first.Name != "progress" || first.Data["completed"] != 0.0 || first.Data["total"] != 3.0 {
+		t.Errorf("first event = %+v, want progress 0 of 3", first)
+	}
+	last := events[len(events)-1]
+	if last.Name != "done" {
+		t.Fatalf("last event = %+v, want done", last)
+	}
+	offers := map[string]int{}
+	for _, event := range events {
+		switch event.Name {
+		case "offers":
+			list := event.Data["offers"].([]any)
+			offers[event.Data["batch"].(string)] = len(list)
+			if first := list[0].(map[string]any); first["id"] != "2" {
+				t.Errorf("batch %s starts with offer %v, want the cheapest", event.Data["batch"], first["id"])
+			}
+		case "error":
+			if event.Data["batch"] != "2025-01-01" || event.Data["status"] != 400.0 || event.Data["details"] == nil {
+				t.Errorf("error event = %+v", event.Data)
+			}
+		}
+	}
+	if offers["2026-12-01"] != 2 || offers["2026-12-02"] != 2 || len(offers) != 2 {
+		t.Errorf("offers per batch = %v", offers)
+	}
+	if last.Data["batches"] != 3.0 || last.Data["failed"] != 1.0 || last.Data["offers"] != 4.0 {
+		t.Errorf("done = %+v", last.Data)
+	}
+	cheapest, _ := last.Data["tags"].(map[string]any)["cheapest"].(map[string]any)
+	if cheapest["id"] != "2" {
+		t.Errorf("cheapest = %v, want offer 2", cheapest)
+	}
+
+	if status := s.do("GET", "/api/search/stream?origen=SCL&destino=LIM&fecha=mañana&adultos=1", nil, nil); status != http.StatusBadRequest {
+		t.Errorf("invalid date: status %d, want 400", status)
+	}
+}
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// itineraryOrder has a connection through an airport missing from the time
+// zone table (USH), so its segment is written in local times.
+const itineraryOrder = `{
+  "type": "flight-order", "id": "eJzTd9f3NjIJdjUwAQALqAJ9",
+  "flightOffers": [{
+    "type": "flight-offer", "id": "1", "source": "GDS",
+    "itineraries": [
+      {"segments": [
+        {
+          "departure": {"iataCode": "SCL", "terminal": "2", "at": "2026-12-01T07:15:00"},
+          "arrival": {"iataCode": "EZE", "at": "2026-12-01T09:20:00"},
+          "carrierCode": "LA", "number": "443", "operating": {"carrierCode": "LA"}, "id": "1"
+        },
+        {
+          "departure": {"iataCode": "EZE", "at": "2026-12-01T11:05:00"},
+          "arrival": {"iataCode": "USH", "at": "2026-12-01T14:40:00"},
+          "carrierCode": "AR", "number": "1872", "operating": {"carrierCode": "AR"}, "id": "2"
+        }
+      ]},
+      {"segments": [
+        {
+          "departure": {"iataCode": "AEP", "at": "2026-12-15T18:30:00"},
+          "arrival": {"iataCode": "SCL", "terminal": "2", "at": "2026-12-15T20:35:00"},
+          "carrierCode": "JA", "number": "735", "operating": {"carrierCode": "H2"}, "id": "3"
+        }
+      ]}
+    ]
+  }]
+}`
+
+func TestItineraryCalendar(t *testing.T) {
+	s := newTestServer(t)
+	id := "eJzTd9f3NjIJdjUwAQALqAJ9"
+	s.amadeus.orders[id] = json.RawMessage(itineraryOrder)
+
+	rec := httptest.NewRecorder()
+	s.handler.ServeHTTP(rec, httptest.NewRequest("GET", "/api/booking/"+id+"/ics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+	// DTSTAMP is the time the file was written.
+	got := regexp.MustCompile(`DTSTAMP:\d{8}T\d{6}Z`).ReplaceAll(rec.Body.Bytes(), []byte("DTSTAMP:20261101T120000Z"))
+
+	golden := filepath.Join("testdata", "itinerary.ics")
+	if *update {
+		if err := os.WriteFile(golden, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("calendar differs from %s (run go test -run TestItineraryCalendar -update to rewrite it):\n%s", golden, got)
+	}
+}
@@ -0,0 +1,163 @@
+// Package farerules turns the free-text fare notes returned by Amadeus with
+// include=detailed-fare-rules into a structured summary: whether the fare is
+// refundable, what changes cost, which penalties apply and how long it is
+// valid.
+//
+// Fare notes are ATPCO rule text written for humans, so parsing is heuristic.
+// Anything that cannot be classified is still returned verbatim in
+// Categories, and an unknown answer is reported as such rather than guessed.
+package farerules
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"go-quickstart/money"
+)
+
+// Note is one description of a detailed fare rule, e.g. the PENALTIES text.
+type Note struct {
+	DescriptionType string `json:"descriptionType"`
+	Text            string `json:"text"`
+}
+
+// Policy says whether an action (refund, change) is allowed and at what cost.
+// A nil Allowed means the rules did not say.
+type Policy struct {
+	Allowed *bool        `json:"allowed,omitempty"`
+	Fee     *money.Money `json:"fee,omitempty"`
+}
+
+// Rules is the structured summary of the fare rules of one fare component.
+type Rules struct {
+	SegmentID  string            `json:"segmentId"`
+	FareBasis  string            `json:"fareBasis"`
+	Name       string            `json:"name,omitempty"`
+	Refund     Policy            `json:"refund"`
+	Change     Policy            `json:"change"`
+	Penalties  []string          `json:"penalties,omitempty"`
+	Validity   []string          `json:"validity,omitempty"`
+	Categories map[string]string `json:"categories,omitempty"`
+}
+
+// amount matches "100", "100.00" and "1,000.00".
+const amount = `(\d{1,3}(?:,\d{3})+(?:\.\d+)?|\d+(?:\.\d+)?)`
+
+var (
+	nonRefundable = regexp.MustCompile(`NON[- ]?REFUNDABLE|NOT REFUNDABLE|REFUND(?:S)? NOT PERMITTED|NO REFUND`)
+	refundable    = regexp.MustCompile(`REFUNDABLE|REFUND(?:S)? PERMITTED|CANCELLATIONS? PERMITTED`)
+	noChanges     = regexp.MustCompile(`CHANGES? (?:ARE )?NOT PERMITTED|NO CHANGES|CHANGES? PROHIBITED`)
+	// "CHANGES ANY TIME" is only a section heading; the text under it says
+	// whether changes are allowed, usually through a charge.
+	changes = regexp.MustCompile(`CHANGES? (?:ARE )?PERMITTED|REISSUE PERMITTED`)
+	// "CHANGES ... CHARGE USD 100.00" / "CANCEL ... FEE EUR 1,000.00"
+	changeFee = regexp.MustCompile(`CHANGES?[^.]*?(?:CHARGE|FEE|PENALTY)\s+([A-Z]{3})\s*` + amount)
+	refundFee = regexp.MustCompile(`(?:CANCEL(?:LATION)?S?|REFUNDS?)[^.]*?(?:CHARGE|FEE|PENALTY)\s+([A-Z]{3})\s*` + amount)
+	// validity related ATPCO categories
+	validityTypes = []string{"STAY", "VALIDITY", "ADVANCE", "SEASON", "DAY", "TIME", "TICKETING"}
+)
+
+// Parse builds the summary of one fare component from its notes.
+func Parse(segmentID, fareBasis, name string, notes []Note) Rules {
+	rules := Rules{
+		SegmentID:  segmentID,
+		FareBasis:  fareBasis,
+		Name:       name,
+		Categories: make(map[string]string, len(notes)),
+	}
+	for _, note := range notes {
+		category := strings.ToUpper(strings.TrimSpace(note.DescriptionType))
+		text := strings.TrimSpace(note.Text)
+		if prev, ok := rules.Categories[category]; ok {
+			text = prev + "\n" + text
+		}
+		rules.Categories[category] = text
+	}
+
+	for _, category := range sortedKeys(rules.Categories) {
+		text := rules.Categories[category]
+		upper := strings.ToUpper(text)
+		switch {
+		case strings.Contains(category, "PENALT"):
+			rules.parsePenalties(upper)
+		case isValidity(category):
+			rules.Validity = append(rules.Validity, lines(text)...)
+		}
+	}
+	return rules
+}
+
+func (r *Rules) parsePenalties(text string) {
+	r.Penalties = append(r.Penalties, lines(text)...)
+
+	// Rule text wraps lines anywhere, so "CHANGES\n NOT PERMITTED" must still
+	// match.
+	text = strings.Join(strings.Fields(text), " ")
+	r.Refund = policy(text, nonRefundable, refundable, refundFee)
+	r.Change = policy(text, noChanges, changes, changeFee)
+}
+
+// policy classifies text as forbidding or allowing an action. When the text
+// says both, as in "CHANGES ANYTIME CHARGE USD 100 ... CHANGES NOT PERMITTED
+// AFTER DEPARTURE", the answer depends on conditions the summary cannot
+// express, so Allowed is left unknown and only the fee is kept.
+func policy(text string, deny, allow, fee *regexp.Regexp) Policy {
+	var p Policy
+	denied := deny.MatchString(text)
+	// Drop the denials first: REFUNDABLE also matches NON-REFUNDABLE.
+	permitted := allow.MatchString(deny.ReplaceAllString(text, ""))
+	p.Fee = findFee(fee, text)
+	switch {
+	case denied && (permitted || p.Fee != nil):
+		return p
+	case denied:
+		p.Allowed = boolPtr(false)
+	case permitted || p.Fee != nil:
+		p.Allowed = boolPtr(true)
+	}
+	return p
+}
+
+func findFee(re *regexp.Regexp, text string) *money.Money {
+	m := re.FindStringSubmatch(text)
+	if m == nil {
+		return nil
+	}
+	fee, err := money.Parse(strings.ReplaceAll(m[2], ",", ""), m[1])
+	if err != nil {
+		return nil
+	}
+	return &fee
+}
+
+func isValidity(category string) bool {
+	for _, t := range validityTypes {
+		if strings.Contains(category, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// lines splits rule text into its non-empty lines.
+func lines(text string) []string {
+	var out []string
+	for _, l := range strings.Split(text, "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func boolPtr(b bool) *bool { return &b }
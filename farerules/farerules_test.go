@@ -0,0 +1,109 @@
+package farerules
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+// load parses a detailed-fare-rules entry as returned by Amadeus pricing with
+// include=detailed-fare-rules.
+func load(t *testing.T, name string) Rules {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rule struct {
+		FareBasis string `json:"fareBasis"`
+		Name      string `json:"name"`
+		FareNotes struct {
+			Descriptions []Note `json:"descriptions"`
+		} `json:"fareNotes"`
+	}
+	if err := json.Unmarshal(data, &rule); err != nil {
+		t.Fatal(err)
+	}
+	return Parse("1", rule.FareBasis, rule.Name, rule.FareNotes.Descriptions)
+}
+
+func TestParseFixtures(t *testing.T) {
+	tests := []struct {
+		file                 string
+		refund, change       string // "true", "false" or "unknown"
+		refundFee, changeFee string
+		validity             int
+	}{
+		{"nonrefundable.json", "false", "true", "", "70.00 EUR", 6},
+		{"conditional.json", "true", "unknown", "1000.00 USD", "100.00 USD", 0},
+		{"flexible.json", "true", "true", "", "", 3},
+		{"nochanges.json", "false", "false", "", "", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			rules := load(t, tt.file)
+			check(t, "refund", rules.Refund, tt.refund, tt.refundFee)
+			check(t, "change", rules.Change, tt.change, tt.changeFee)
+			if len(rules.Penalties) == 0 {
+				t.Error("penalty text was not kept")
+			}
+			if len(rules.Validity) != tt.validity {
+				t.Errorf("validity = %q, want %d lines", rules.Validity, tt.validity)
+			}
+		})
+	}
+}
+
+func check(t *testing.T, action string, p Policy, allowed, fee string) {
+	t.Helper()
+	if got := state(p); got != allowed {
+		t.Errorf("%s allowed = %s, want %s", action, got, allowed)
+	}
+	gotFee := ""
+	if p.Fee != nil {
+		gotFee = p.Fee.String()
+	}
+	if gotFee != fee {
+		t.Errorf("%s fee = %q, want %q", action, gotFee, fee)
+	}
+}
+
+// state is "true", "false" or "unknown" for the Allowed of p.
+func state(p Policy) string {
+	if p.Allowed == nil {
+		return "unknown"
+	}
+	return strconv.FormatBool(*p.Allowed)
+}
+
+func TestParsePenaltyPhrases(t *testing.T) {
+	tests := []struct {
+		text           string
+		refund, change string
+	}{
+		{"CHANGES ANYTIME CHARGE USD 100 ... CHANGES NOT PERMITTED AFTER DEPARTURE", "unknown", "unknown"},
+		{"TICKET IS REFUNDABLE. NO REFUND AFTER DEPARTURE.", "unknown", "unknown"},
+		{"TICKET IS NON-REFUNDABLE.", "false", "unknown"},
+		{"CANCELLATIONS PERMITTED.\nCHANGES\n  NOT PERMITTED.", "true", "false"},
+		{"CANCEL BEFORE DEPARTURE FEE EUR 1,250.50.", "true", "unknown"},
+		{"NO SPECIFIC PENALTIES.", "unknown", "unknown"},
+	}
+	for _, tt := range tests {
+		rules := Parse("1", "Y", "", []Note{{DescriptionType: "PENALTIES", Text: tt.text}})
+		for _, c := range []struct {
+			action string
+			p      Policy
+			want   string
+		}{{"refund", rules.Refund, tt.refund}, {"change", rules.Change, tt.change}} {
+			if got := state(c.p); got != c.want {
+				t.Errorf("%q: %s allowed = %s, want %s", tt.text, c.action, got, c.want)
+			}
+		}
+	}
+	rules := Parse("1", "Y", "", []Note{{DescriptionType: "PENALTIES", Text: "CANCEL BEFORE DEPARTURE FEE EUR 1,250.50."}})
+	if rules.Refund.Fee == nil || rules.Refund.Fee.String() != "1250.50 EUR" {
+		t.Errorf("refund fee = %v, want 1250.50 EUR", rules.Refund.Fee)
+	}
+}
@@ -0,0 +1,523 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// The GraphQL API at /graphql offers the operations of the REST API, so
+// clients fetch only the fields they render. Its types have the names and
+// fields of the Amadeus documents: results are converted to maps through
+// JSON and resolved by field name.
+
+// graphQLRequest is the body of a POST /graphql.
+type graphQLRequest struct {
+	Query         string         `json:"query" binding:"required"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// graphQLResponse is the result of a GraphQL request.
+type graphQLResponse struct {
+	Data   any              `json:"data,omitempty"`
+	Errors []graphQLMessage `json:"errors,omitempty"`
+}
+
+type graphQLMessage struct {
+	Message    string         `json:"message"`
+	Path       []any          `json:"path,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+var graphQLSchema = sync.OnceValues(newGraphQLSchema)
+
+// graphqlHandler runs a GraphQL query or mutation. Like most GraphQL servers
+// it answers 200 even when resolvers fail; the errors carry the status the
+// REST API would have answered in extensions.status.
+func graphqlHandler(c *gin.Context) {
+	var request graphQLRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	schema, err := graphQLSchema()
+	if err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	result := graphql.Do(graphql.Params{
+		Schema:         schema,
+		RequestString:  request.Query,
+		OperationName:  request.OperationName,
+		VariableValues: request.Variables,
+		Context:        c.Request.Context(),
+	})
+
+	response := graphQLResponse{Data: result.Data}
+	for _, e := range result.Errors {
+		response.Errors = append(response.Errors, graphQLMessage{Message: e.Message, Path: e.Path, Extensions: e.Extensions})
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// graphQLError is a resolver error with the REST status in its extensions.
+type graphQLError struct {
+	err error
+}
+
+func (e graphQLError) Error() string { return e.err.Error() }
+
+func (e graphQLError) Extensions() map[string]any {
+	status := http.StatusBadRequest
+	var inputErr *inputError
+	if !errors.As(e.err, &inputErr) {
+		status = upstreamStatus(e.err)
+	}
+	extensions := map[string]any{"status": status}
+	var providerErr *providerError
+	if errors.As(e.err, &providerErr) && providerErr.Body != nil {
+		extensions["details"] = providerErr.Body
+	}
+	return extensions
+}
+
+// toGraph converts v into the maps and slices its JSON decodes to.
+func toGraph(v any) (any, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var graph any
+	err = json.Unmarshal(raw, &graph)
+	return graph, err
+}
+
+// fromGraph decodes GraphQL arguments into v.
+func fromGraph(arg any, v any) error {
+	raw, err := json.Marshal(arg)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return invalidInput(err)
+	}
+	return nil
+}
+
+func stringArg(p graphql.ResolveParams, name string) string {
+	s, _ := p.Args[name].(string)
+	return s
+}
+
+func intArg(p graphql.ResolveParams, name string) int {
+	n, _ := p.Args[name].(int)
+	return n
+}
+
+// jsonType is any JSON value, used for the offers passed back to pricing
+// and booking and for maps.
+var jsonType = graphql.NewScalar(graphql.ScalarConfig{
+	Name:         "JSON",
+	Description:  "Any JSON value.",
+	Serialize:    func(v any) any { return v },
+	ParseValue:   func(v any) any { return v },
+	ParseLiteral: jsonLiteral,
+})
+
+func jsonLiteral(v ast.Value) any {
+	switch v := v.(type) {
+	case *ast.StringValue:
+		return v.Value
+	case *ast.BooleanValue:
+		return v.Value
+	case *ast.EnumValue:
+		return v.Value
+	case *ast.IntValue:
+		n, _ := strconv.ParseInt(v.Value, 10, 64)
+		return n
+	case *ast.FloatValue:
+		f, _ := strconv.ParseFloat(v.Value, 64)
+		return f
+	case *ast.ListValue:
+		list := make([]any, len(v.Values))
+		for i, value := range v.Values {
+			list[i] = jsonLiteral(value)
+		}
+		return list
+	case *ast.ObjectValue:
+		object := make(map[string]any, len(v.Fields))
+		for _, field := range v.Fields {
+			object[field.Name.Value] = jsonLiteral(field.Value)
+		}
+		return object
+	}
+	return nil
+}
+
+// graphFields declares fields of type t resolved by name.
+func graphFields(t graphql.Output, names ...string) graphql.Fields {
+	f := graphql.Fields{}
+	for _, name := range names {
+		f[name] = &graphql.Field{Type: t}
+	}
+	return f
+}
+
+// withFields adds more fields to f.
+func withFields(f graphql.Fields, more graphql.Fields) graphql.Fields {
+	for name, field := range more {
+		f[name] = field
+	}
+	return f
+}
+
+func graphList(t graphql.Type) *graphql.List { return graphql.NewList(graphql.NewNonNull(t)) }
+
+func newGraphQLSchema() (graphql.Schema, error) {
+	money := graphql.NewObject(graphql.ObjectConfig{Name: "Money", Fields: graphFields(graphql.String, "amount", "currency")})
+	fee := graphql.NewObject(graphql.ObjectConfig{Name: "Fee", Fields: graphFields(graphql.String, "amount", "type")})
+	price := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Price",
+		Description: "Amounts are decimal strings in currency.",
+		Fields: withFields(graphFields(graphql.String, "currency", "total", "base", "grandTotal", "billingCurrency"), graphql.Fields{
+			"fees": {Type: graphList(fee)},
+		}),
+	})
+	displayPrice := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "DisplayPrice",
+		Description: "A price converted to the requested currency. The offer is still billed in billingCurrency.",
+		Fields: withFields(graphFields(graphql.String, "currency", "total", "grandTotal", "billingCurrency", "billedTotal", "billedGrandTotal", "exchangeRate", "ratesUpdatedAt"), graphql.Fields{
+			"converted": {Type: graphql.Boolean},
+		}),
+	})
+	scores := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Scores",
+		Description: "From 0 to 1, 1 being the best offer of the search.",
+		Fields:      graphFields(graphql.Float, "price", "duration", "stops", "departure", "best"),
+	})
+
+	endpoint := graphql.NewObject(graphql.ObjectConfig{Name: "Endpoint", Fields: graphFields(graphql.String, "iataCode", "terminal", "at")})
+	aircraft := graphql.NewObject(graphql.ObjectConfig{Name: "Aircraft", Fields: graphFields(graphql.String, "code")})
+	operating := graphql.NewObject(graphql.ObjectConfig{Name: "Operating", Fields: graphFields(graphql.String, "carrierCode")})
+	segment := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Segment",
+		Fields: withFields(graphFields(graphql.String, "id", "carrierCode", "number", "duration"), graphql.Fields{
+			"departure":       {Type: endpoint},
+			"arrival":         {Type: endpoint},
+			"aircraft":        {Type: aircraft},
+			"operating":       {Type: operating, Description: "Filled with carrierCode when Amadeus leaves it out."},
+			"numberOfStops":   {Type: graphql.Int},
+			"blacklistedInEU": {Type: graphql.Boolean},
+		}),
+	})
+	itinerary := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Itinerary",
+		Fields: graphql.Fields{
+			"duration": {Type: graphql.String, Description: "ISO 8601, such as PT3H40M."},
+			"segments": {Type: graphList(segment)},
+		},
+	})
+
+	checkedBags := graphql.NewObject(graphql.ObjectConfig{
+		Name:   "CheckedBags",
+		Fields: withFields(graphFields(graphql.Int, "quantity", "weight"), graphFields(graphql.String, "weightUnit")),
+	})
+	fareDetails := graphql.NewObject(graphql.ObjectConfig{
+		Name: "FareDetails",
+		Fields: withFields(graphFields(graphql.String, "segmentId", "cabin", "fareBasis", "class"), graphql.Fields{
+			"includedCheckedBags": {Type: checkedBags},
+		}),
+	})
+	travelerPricing := graphql.NewObject(graphql.ObjectConfig{
+		Name: "TravelerPricing",
+		Fields: withFields(graphFields(graphql.String, "travelerId", "fareOption", "travelerType"), graphql.Fields{
+			"price":                {Type: price},
+			"fareDetailsBySegment": {Type: graphList(fareDetails)},
+		}),
+	})
+	flightOffer := graphql.NewObject(graphql.ObjectConfig{
+		Name: "FlightOffer",
+		Fields: withFields(graphFields(graphql.String, "id", "type", "source", "provider", "lastTicketingDate"), graphql.Fields{
+			"oneWay":                   {Type: graphql.Boolean},
+			"instantTicketingRequired": {Type: graphql.Boolean},
+			"numberOfBookableSeats":    {Type: graphql.Int},
+			"validatingAirlineCodes":   {Type: graphList(graphql.String)},
+			"itineraries":              {Type: graphList(itinerary)},
+			"price":                    {Type: price},
+			"displayPrice":             {Type: displayPrice},
+			"scores":                   {Type: scores},
+			"tags":                     {Type: graphList(graphql.String), Description: "cheapest, fastest and best."},
+			"travelerPricings":         {Type: graphList(travelerPricing)},
+			"document": {
+				Type:        jsonType,
+				Description: "The whole offer, to pass to priceOffers and createBooking.",
+				Resolve:     func(p graphql.ResolveParams) (any, error) { return p.Source, nil },
+			},
+		}),
+	})
+
+	searchMeta := graphql.NewObject(graphql.ObjectConfig{
+		Name:   "SearchMeta",
+		Fields: withFields(graphFields(graphql.Int, "count", "total", "offset"), graphFields(graphql.String, "nextCursor")),
+	})
+	offerPage := graphql.NewObject(graphql.ObjectConfig{
+		Name: "OfferPage",
+		Fields: graphql.Fields{
+			"meta": {Type: graphql.NewNonNull(searchMeta)},
+			"offers": {
+				Type: graphql.NewNonNull(graphList(flightOffer)),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					page, _ := p.Source.(map[string]any)
+					return page["data"], nil
+				},
+			},
+		},
+	})
+
+	policy := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Policy",
+		Fields: graphql.Fields{
+			"allowed": {Type: graphql.Boolean, Description: "Null when the rules do not say."},
+			"fee":     {Type: money},
+		},
+	})
+	fareRules := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "FareRules",
+		Description: "Summary of the fare rules of one fare component.",
+		Fields: withFields(graphFields(graphql.String, "segmentId", "fareBasis", "name"), graphql.Fields{
+			"refund":     {Type: policy},
+			"change":     {Type: policy},
+			"penalties":  {Type: graphList(graphql.String)},
+			"validity":   {Type: graphList(graphql.String)},
+			"categories": {Type: jsonType},
+		}),
+	})
+	pricing := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Pricing",
+		Fields: graphql.Fields{
+			"offers":    {Type: graphql.NewNonNull(graphList(flightOffer))},
+			"fareRules": {Type: graphList(fareRules)},
+		},
+	})
+
+	name := graphql.NewObject(graphql.ObjectConfig{Name: "Name", Fields: graphFields(graphql.String, "firstName", "lastName")})
+	phone := graphql.NewObject(graphql.ObjectConfig{Name: "Phone", Fields: graphFields(graphql.String, "deviceType", "countryCallingCode", "number")})
+	contact := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Contact",
+		Fields: graphql.Fields{
+			"emailAddress": {Type: graphql.String},
+			"phones":       {Type: graphList(phone)},
+		},
+	})
+	traveler := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Traveler",
+		Fields: withFields(graphFields(graphql.String, "id", "dateOfBirth", "gender"), graphql.Fields{
+			"name":    {Type: name},
+			"contact": {Type: contact},
+		}),
+	})
+	order := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Order",
+		Fields: withFields(graphFields(graphql.String, "id", "type"), graphql.Fields{
+			"travelers":    {Type: graphList(traveler)},
+			"flightOffers": {Type: graphList(flightOffer)},
+		}),
+	})
+	booking := graphql.NewObject(graphql.ObjectConfig{
+		Name:   "Booking",
+		Fields: graphql.Fields{"orderId": {Type: graphql.NewNonNull(graphql.ID)}},
+	})
+
+	inputFields := func(t graphql.Input, names ...string) graphql.InputObjectConfigFieldMap {
+		f := graphql.InputObjectConfigFieldMap{}
+		for _, name := range names {
+			f[name] = &graphql.InputObjectFieldConfig{Type: t}
+		}
+		return f
+	}
+	nameInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:   "NameInput",
+		Fields: inputFields(graphql.NewNonNull(graphql.String), "firstName", "lastName"),
+	})
+	phoneInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:   "PhoneInput",
+		Fields: inputFields(graphql.String, "deviceType", "countryCallingCode", "number"),
+	})
+	contactInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "ContactInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"emailAddress": {Type: graphql.String},
+			"phones":       {Type: graphList(phoneInput)},
+		},
+	})
+	documentFields := inputFields(graphql.String, "documentType", "birthPlace", "issuanceLocation", "issuanceDate", "number", "expiryDate", "issuanceCountry", "validityCountry", "nationality")
+	documentFields["holder"] = &graphql.InputObjectFieldConfig{Type: graphql.Boolean}
+	documentInput := graphql.NewInputObject(graphql.InputObjectConfig{Name: "DocumentInput", Fields: documentFields})
+	travelerFields := inputFields(graphql.NewNonNull(graphql.String), "id", "dateOfBirth", "gender")
+	travelerFields["name"] = &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(nameInput)}
+	travelerFields["contact"] = &graphql.InputObjectFieldConfig{Type: contactInput}
+	travelerFields["documents"] = &graphql.InputObjectFieldConfig{Type: graphList(documentInput)}
+	travelerInput := graphql.NewInputObject(graphql.InputObjectConfig{Name: "TravelerInput", Fields: travelerFields})
+
+	currencyArg := &graphql.ArgumentConfig{Type: graphql.String, Description: "Currency to also show prices in, as displayPrice."}
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"searchFlights": {
+				Type:        graphql.NewNonNull(offerPage),
+				Description: "Offers sorted, filtered and scored as in GET /api/search.",
+				Args: graphql.FieldConfigArgument{
+					"origin":        {Type: graphql.NewNonNull(graphql.String)},
+					"destination":   {Type: graphql.NewNonNull(graphql.String)},
+					"departureDate": {Type: graphql.NewNonNull(graphql.String), Description: "YYYY-MM-DD."},
+					"adults":        {Type: graphql.Int, DefaultValue: 1},
+					"currency":      currencyArg,
+					"sort":          {Type: graphql.String, Description: `price, duration, departure, arrival or stops; "-" sorts descending.`},
+					"carriers":      {Type: graphList(graphql.String)},
+					"departAfter":   {Type: graphql.String, Description: "HH:MM."},
+					"departBefore":  {Type: graphql.String, Description: "HH:MM."},
+					"maxDuration":   {Type: graphql.String, Description: "ISO 8601, such as PT6H."},
+					"maxPrice":      {Type: graphql.String, Description: "In the billing currency."},
+					"bagsIncluded":  {Type: graphql.Boolean},
+					"limit":         {Type: graphql.Int},
+					"offset":        {Type: graphql.Int},
+					"cursor":        {Type: graphql.String},
+					"weights":       {Type: graphql.String, Description: `Weights of the "best" score, such as price=0.6,duration=0.4.`},
+				},
+				Resolve: resolveSearch,
+			},
+			"order": {
+				Type: order,
+				Args: graphql.FieldConfigArgument{
+					"id":       {Type: graphql.NewNonNull(graphql.ID)},
+					"currency": currencyArg,
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					orderResponse, err := retrieveOrder(p.Context, stringArg(p, "id"), stringArg(p, "currency"))
+					if err != nil {
+						return nil, graphQLError{err}
+					}
+					return toGraph(orderResponse.Data)
+				},
+			},
+		},
+	})
+
+	offersArg := &graphql.ArgumentConfig{
+		Type:        graphql.NewNonNull(graphList(jsonType)),
+		Description: "The document of each offer, as returned by searchFlights or priceOffers.",
+	}
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"priceOffers": {
+				Type: graphql.NewNonNull(pricing),
+				Args: graphql.FieldConfigArgument{
+					"offers":    offersArg,
+					"fareRules": {Type: graphql.Boolean, Description: "Include the summary of the fare rules."},
+					"currency":  currencyArg,
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					var searchPrice FlightPriceRequest
+					searchPrice.Data.Type = "flight-offers-pricing"
+					if err := fromGraph(p.Args["offers"], &searchPrice.Data.FlightOffers); err != nil {
+						return nil, graphQLError{err}
+					}
+					withFareRules, _ := p.Args["fareRules"].(bool)
+					pricingResponse, err := priceOffers(p.Context, searchPrice, withFareRules, stringArg(p, "currency"))
+					if err != nil {
+						return nil, graphQLError{err}
+					}
+					return toGraph(map[string]any{
+						"offers":    pricingResponse.Data.FlightOffers,
+						"fareRules": pricingResponse.FareRules,
+					})
+				},
+			},
+			"createBooking": {
+				Type:        graphql.NewNonNull(booking),
+				Description: "Books priced offers and emails the confirmation.",
+				Args: graphql.FieldConfigArgument{
+					"offers":    offersArg,
+					"travelers": {Type: graphql.NewNonNull(graphList(travelerInput))},
+					"language":  {Type: graphql.String, Description: "Language of the confirmation email: es or en."},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					var bookingRequest BookingRequest
+					bookingRequest.Data.Type = "flight-order"
+					if err := fromGraph(p.Args["offers"], &bookingRequest.Data.FlightOffers); err != nil {
+						return nil, graphQLError{err}
+					}
+					if err := fromGraph(p.Args["travelers"], &bookingRequest.Data.Travelers); err != nil {
+						return nil, graphQLError{err}
+					}
+					bookingResponse, err := createBooking(p.Context, bookingRequest, stringArg(p, "language"))
+					if err != nil {
+						return nil, graphQLError{err}
+					}
+					return map[string]any{"orderId": bookingResponse.Data.ID}, nil
+				},
+			},
+			"cancelBooking": {
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: graphql.FieldConfigArgument{"id": {Type: graphql.NewNonNull(graphql.ID)}},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					if err := cancelBooking(p.Context, stringArg(p, "id")); err != nil {
+						return nil, graphQLError{err}
+					}
+					return true, nil
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query, Mutation: mutation})
+}
+
+func resolveSearch(p graphql.ResolveParams) (any, error) {
+	var carriers []string
+	if list, ok := p.Args["carriers"].([]any); ok {
+		for _, carrier := range list {
+			carriers = append(carriers, carrier.(string))
+		}
+	}
+	bags, _ := p.Args["bagsIncluded"].(bool)
+	opts, err := searchFilters{
+		Sort:         stringArg(p, "sort"),
+		Carriers:     carriers,
+		DepartAfter:  stringArg(p, "departAfter"),
+		DepartBefore: stringArg(p, "departBefore"),
+		MaxDuration:  stringArg(p, "maxDuration"),
+		MaxPrice:     stringArg(p, "maxPrice"),
+		BagsIncluded: bags,
+		Limit:        intArg(p, "limit"),
+		Offset:       intArg(p, "offset"),
+		Cursor:       stringArg(p, "cursor"),
+	}.options()
+	if err != nil {
+		return nil, graphQLError{err}
+	}
+	weights, err := scoreWeights(stringArg(p, "weights"))
+	if err != nil {
+		return nil, graphQLError{invalidInput(err)}
+	}
+
+	offers, err := searchOffers(p.Context, searchParams{
+		Origen:      stringArg(p, "origin"),
+		Destino:     stringArg(p, "destination"),
+		FechaSalida: stringArg(p, "departureDate"),
+		Adultos:     strconv.Itoa(intArg(p, "adults")),
+		Moneda:      stringArg(p, "currency"),
+	}, opts, weights)
+	if err != nil {
+		return nil, graphQLError{err}
+	}
+	return toGraph(offers)
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+// graphQL runs a GraphQL request against s and decodes its data into out.
+func (s *testServer) graphQL(query string, variables map[string]any, out any) []graphQLMessage {
+	s.t.Helper()
+	var response struct {
+		Data   json.RawMessage  `json:"data"`
+		Errors []graphQLMessage `json:"errors"`
+	}
+	if status := s.do("POST", "/graphql", graphQLRequest{Query: query, Variables: variables}, &response); status != http.StatusOK {
+		s.t.Fatalf("POST /graphql = %d, want 200", status)
+	}
+	if out != nil && response.Data != nil {
+		if err := json.Unmarshal(response.Data, out); err != nil {
+			s.t.Fatal(err)
+		}
+	}
+	return response.Errors
+}
+
+func TestGraphQLSearchPriceBookAndCancel(t *testing.T) {
+	s := newTestServer(t)
+
+	var search struct {
+		SearchFlights struct {
+			Meta   SearchMeta       `json:"meta"`
+			Offers []map[string]any `json:"offers"`
+		} `json:"searchFlights"`
+	}
+	errs := s.graphQL(`{
+		searchFlights(origin: "SCL", destination: "LIM", departureDate: "2026-12-01", sort: "price", limit: 1) {
+			meta { total count nextCursor }
+			offers { id tags price { grandTotal currency } itineraries { segments { operating { carrierCode } } } document }
+		}
+	}`, nil, &search)
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	page := search.SearchFlights
+	if page.Meta.Total != 2 || page.Meta.Count != 1 || page.Meta.NextCursor == "" || len(page.Offers) != 1 {
+		t.Fatalf("page = %+v", page)
+	}
+	offer := page.Offers[0]
+	if _, ok := offer["travelerPricings"]; ok {
+		t.Error("travelerPricings returned without being selected")
+	}
+	if offer["id"] != "2" {
+		t.Errorf("cheapest offer = %v, want 2", offer["id"])
+	}
+
+	var pricing struct {
+		PriceOffers struct {
+			Offers []struct {
+				Document map[string]any `json:"document"`
+			} `json:"offers"`
+		} `json:"priceOffers"`
+	}
+	errs = s.graphQL(`mutation($offers: [JSON!]!) {
+		priceOffers(offers: $offers) { offers { document } }
+	}`, map[string]any{"offers": []any{offer["document"]}}, &pricing)
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+
+	var traveler map[string]any
+	json.Unmarshal([]byte(travelerFixture), &traveler)
+	var booking struct {
+		CreateBooking struct {
+			OrderID string `json:"orderId"`
+		} `json:"createBooking"`
+	}
+	errs = s.graphQL(`mutation($offers: [JSON!]!, $travelers: [TravelerInput!]!) {
+		createBooking(offers: $offers, travelers: $travelers, language: "en") { orderId }
+	}`, map[string]any{
+		"offers":    []any{pricing.PriceOffers.Offers[0].Document},
+		"travelers": []any{traveler},
+	}, &booking)
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	orderID := booking.CreateBooking.OrderID
+	if orderID == "" || len(s.bookings.list) != 1 {
+		t.Fatalf("order %q, %d bookings saved", orderID, len(s.bookings.list))
+	}
+
+	var order struct {
+		Order struct {
+			ID        string `json:"id"`
+			Travelers []struct {
+				Name struct {
+					LastName string `json:"lastName"`
+				} `json:"name"`
+			} `json:"travelers"`
+		} `json:"order"`
+	}
+	errs = s.graphQL(`query($id: ID!) { order(id: $id) { id travelers { name { lastName } } } }`, map[string]any{"id": orderID}, &order)
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	if order.Order.ID != orderID || len(order.Order.Travelers) != 1 || order.Order.Travelers[0].Name.LastName != "ROJAS" {
+		t.Errorf("order = %+v", order.Order)
+	}
+
+	errs = s.graphQL(`mutation($id: ID!) { cancelBooking(id: $id) }`, map[string]any{"id": orderID}, nil)
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	errs = s.graphQL(`query($id: ID!) { order(id: $id) { id } }`, map[string]any{"id": orderID}, nil)
+	if len(errs) != 1 || errs[0].Extensions["status"] != float64(http.StatusNotFound) {
+		t.Errorf("cancelled order: errors = %+v, want status 404", errs)
+	}
+}
+
+func TestGraphQLErrors(t *testing.T) {
+	s := newTestServer(t)
+
+	errs := s.graphQL(`{ searchFlights(origin: "SCL", destination: "LIM", departureDate: "2026-12-01", sort: "cheapest") { meta { total } } }`, nil, nil)
+	if len(errs) != 1 || errs[0].Extensions["status"] != float64(http.StatusBadRequest) {
+		t.Errorf("invalid sort: errors = %+v, want status 400", errs)
+	}
+	if n := s.amadeus.count("GET", "/v2/shopping/flight-offers"); n != 0 {
+		t.Errorf("invalid search was sent to Amadeus %d times", n)
+	}
+
+	errs = s.graphQL(`{ searchFlights(origin: "SANTIAGO", destination: "LIM", departureDate: "2026-12-01") { meta { total } } }`, nil, nil)
+	if len(errs) != 1 || errs[0].Extensions["status"] != float64(http.StatusBadRequest) || errs[0].Extensions["details"] == nil {
+		t.Errorf("rejected by Amadeus: errors = %+v, want status 400 with details", errs)
+	}
+
+	errs = s.graphQL(`{ searchFlights(origin: "SCL") { meta { total } } }`, nil, nil)
+	if len(errs) == 0 {
+		t.Error("missing arguments were accepted")
+	}
+
+	if status := s.do("POST", "/graphql", map[string]any{}, nil); status != http.StatusBadRequest {
+		t.Errorf("no query: status %d, want 400", status)
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+//go:generate buf generate proto
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"go-quickstart/logging"
+	"go-quickstart/metrics"
+	gotravelv1 "go-quickstart/proto/gotravel/v1"
+)
+
+// newGRPCServer returns the gRPC API, with the same logging, request IDs,
+// tracing and metrics as the REST one. The metrics interceptors wrap the
+// logging ones, which recover panics, so those calls are counted as Internal.
+func newGRPCServer() *grpc.Server {
+	srv := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(metrics.UnaryInterceptor, logging.UnaryInterceptor),
+		grpc.ChainStreamInterceptor(metrics.StreamInterceptor, logging.StreamInterceptor),
+	)
+	gotravelv1.RegisterFlightsServiceServer(srv, flightsService{})
+	reflection.Register(srv)
+	return srv
+}
+
+// flightsService implements the gRPC API over the functions in service.go.
+type flightsService struct {
+	gotravelv1.UnimplementedFlightsServiceServer
+}
+
+func (flightsService) SearchFlights(req *gotravelv1.SearchFlightsRequest, stream gotravelv1.FlightsService_SearchFlightsServer) error {
+	ctx := stream.Context()
+	if req.GetAdults() < 1 {
+		return status.Error(codes.InvalidArgument, "adults must be at least 1")
+	}
+	opts, err := searchFilters{
+		Sort:         req.GetSort(),
+		Carriers:     req.GetCarriers(),
+		DepartAfter:  req.GetDepartAfter(),
+		DepartBefore: req.GetDepartBefore(),
+		MaxDuration:  req.GetMaxDuration(),
+		MaxPrice:     req.GetMaxPrice(),
+		BagsIncluded: req.GetBagsIncluded(),
+		Limit:        int(req.GetLimit()),
+		Offset:       int(req.GetOffset()),
+		Cursor:       req.GetCursor(),
+	}.options()
+	if err != nil {
+		return grpcError(err)
+	}
+	weights, err := scoreWeights(req.GetWeights())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	offers, err := searchOffers(ctx, searchParams{
+		Origen:      req.GetOrigin(),
+		Destino:     req.GetDestination(),
+		FechaSalida: req.GetDepartureDate(),
+		Adultos:     strconv.Itoa(int(req.GetAdults())),
+		Moneda:      req.GetDisplayCurrency(),
+	}, opts, weights)
+	if err != nil {
+		return grpcError(err)
+	}
+
+	meta := &gotravelv1.SearchMeta{
+		Count:      int32(offers.Meta.Count),
+		Total:      int32(offers.Meta.Total),
+		Offset:     int32(offers.Meta.Offset),
+		NextCursor: offers.Meta.NextCursor,
+	}
+	if err := stream.Send(&gotravelv1.SearchFlightsResponse{Result: &gotravelv1.SearchFlightsResponse_Meta{Meta: meta}}); err != nil {
+		return err
+	}
+	for _, offer := range offers.Data {
+		var msg gotravelv1.FlightOffer
+		if err := toMessage(offer, &msg); err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		if err := stream.Send(&gotravelv1.SearchFlightsResponse{Result: &gotravelv1.SearchFlightsResponse_Offer{Offer: &msg}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (flightsService) PriceOffers(ctx context.Context, req *gotravelv1.PriceOffersRequest) (*gotravelv1.PriceOffersResponse, error) {
+	var searchPrice FlightPriceRequest
+	searchPrice.Data.Type = "flight-offers-pricing"
+	if err := fromMessages(req.GetFlightOffers(), &searchPrice.Data.FlightOffers); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	pricingResponse, err := priceOffers(ctx, searchPrice, req.GetFareRules(), req.GetDisplayCurrency())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	var resp gotravelv1.PriceOffersResponse
+	if err := toMessage(map[string]any{
+		"flightOffers": pricingResponse.Data.FlightOffers,
+		"fareRules":    pricingResponse.FareRules,
+	}, &resp); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &resp, nil
+}
+
+func (flightsService) CreateBooking(ctx context.Context, req *gotravelv1.CreateBookingRequest) (*gotravelv1.CreateBookingResponse, error) {
+	var bookingRequest BookingRequest
+	bookingRequest.Data.Type = "flight-order"
+	if err := fromMessages(req.GetFlightOffers(), &bookingRequest.Data.FlightOffers); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := fromMessages(req.GetTravelers(), &bookingRequest.Data.Travelers); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	lang := req.GetLanguage()
+	if md, ok := metadata.FromIncomingContext(ctx); ok && lang == "" {
+		if values := md.Get("accept-language"); len(values) > 0 {
+			lang = values[0]
+		}
+	}
+	bookingResponse, err := createBooking(ctx, bookingRequest, lang)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &gotravelv1.CreateBookingResponse{OrderId: bookingResponse.Data.ID}, nil
+}
+
+func (flightsService) GetBooking(ctx context.Context, req *gotravelv1.GetBookingRequest) (*gotravelv1.GetBookingResponse, error) {
+	if req.GetOrderId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
+	orderResponse, err := retrieveOrder(ctx, req.GetOrderId(), req.GetDisplayCurrency())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	var order gotravelv1.Order
+	if err := toMessage(orderResponse.Data, &order); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &gotravelv1.GetBookingResponse{Order: &order}, nil
+}
+
+func (flightsService) CancelBooking(ctx context.Context, req *gotravelv1.CancelBookingRequest) (*gotravelv1.CancelBookingResponse, error) {
+	if req.GetOrderId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
+	if err := cancelBooking(ctx, req.GetOrderId()); err != nil {
+		return nil, grpcError(err)
+	}
+	return &gotravelv1.CancelBookingResponse{}, nil
+}
+
+// grpcError is upstreamStatus for gRPC: a failed provider call is
+// Unavailable unless the provider rejected the request itself. The errors it
+// returned are passed on in the message.
+func grpcError(err error) error {
+	var inputErr *inputError
+	if errors.As(err, &inputErr) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, context.Canceled) {
+		return status.Error(codes.Canceled, err.Error())
+	}
+	msg := err.Error()
+	var providerErr *providerError
+	if errors.As(err, &providerErr) && providerErr.Body != nil {
+		msg += ": " + string(providerErr.Body)
+	}
+	switch upstreamStatus(err) {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return status.Error(codes.InvalidArgument, msg)
+	case http.StatusNotFound:
+		return status.Error(codes.NotFound, msg)
+	case http.StatusGatewayTimeout:
+		return status.Error(codes.DeadlineExceeded, msg)
+	}
+	return status.Error(codes.Unavailable, msg)
+}
+
+// The messages of the gRPC API have the JSON names of the Amadeus documents,
+// so they are converted through JSON.
+
+var unmarshalMessage = protojson.UnmarshalOptions{DiscardUnknown: true}
+
+// toMessage converts v, an Amadeus document, into m.
+func toMessage(v any, m proto.Message) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return unmarshalMessage.Unmarshal(raw, m)
+}
+
+// fromMessages converts messages into the Amadeus documents in v, a pointer
+// to a slice.
+func fromMessages[M proto.Message](messages []M, v any) error {
+	list := make([]json.RawMessage, len(messages))
+	for i, m := range messages {
+		raw, err := protojson.Marshal(m)
+		if err != nil {
+			return err
+		}
+		list[i] = raw
+	}
+	raw, err := json.Marshal(list)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
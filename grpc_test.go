@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	gotravelv1 "go-quickstart/proto/gotravel/v1"
+)
+
+// newGRPCClient serves the gRPC API of s in memory and returns a client.
+func (s *testServer) newGRPCClient() gotravelv1.FlightsServiceClient {
+	s.t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := newGRPCServer()
+	go srv.Serve(lis)
+	s.t.Cleanup(srv.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	s.t.Cleanup(func() { conn.Close() })
+	return gotravelv1.NewFlightsServiceClient(conn)
+}
+
+// searchAll runs SearchFlights and collects the stream.
+func searchAll(t *testing.T, client gotravelv1.FlightsServiceClient, req *gotravelv1.SearchFlightsRequest) (*gotravelv1.SearchMeta, []*gotravelv1.FlightOffer, error) {
+	t.Helper()
+	stream, err := client.SearchFlights(context.Background(), req)
+	if err != nil {
+		return nil, nil, err
+	}
+	var meta *gotravelv1.SearchMeta
+	var offers []*gotravelv1.FlightOffer
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return meta, offers, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if m := resp.GetMeta(); m != nil {
+			if meta != nil || len(offers) > 0 {
+				t.Fatal("meta is not the first message of the stream")
+			}
+			meta = m
+		}
+		if offer := resp.GetOffer(); offer != nil {
+			offers = append(offers, offer)
+		}
+	}
+}
+
+func TestGRPCSearchPriceBookGetAndCancel(t *testing.T) {
+	s := newTestServer(t)
+	client := s.newGRPCClient()
+	ctx := context.Background()
+
+	meta, offers, err := searchAll(t, client, &gotravelv1.SearchFlightsRequest{
+		Origin: "SCL", Destination: "LIM", DepartureDate: "2026-12-01", Adults: 1, Sort: "price",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.GetTotal() != 2 || len(offers) != 2 {
+		t.Fatalf("got meta %v and %d offers, want 2", meta, len(offers))
+	}
+	if offers[0].GetScores() == nil || len(offers[0].GetTags()) == 0 {
+		t.Errorf("offer %s is not scored", offers[0].GetId())
+	}
+	for _, offer := range offers {
+		for _, segment := range offer.GetItineraries()[0].GetSegments() {
+			if segment.GetOperating().GetCarrierCode() == "" {
+				t.Errorf("offer %s segment %s has no operating carrier", offer.GetId(), segment.GetId())
+			}
+		}
+	}
+
+	priced, err := client.PriceOffers(ctx, &gotravelv1.PriceOffersRequest{FlightOffers: offers[:1]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := priced.GetFlightOffers(); len(got) != 1 || got[0].GetPrice().GetGrandTotal() != offers[0].GetPrice().GetGrandTotal() {
+		t.Fatalf("priced offers = %v", got)
+	}
+
+	var traveler gotravelv1.Traveler
+	if err := protojson.Unmarshal([]byte(travelerFixture), &traveler); err != nil {
+		t.Fatal(err)
+	}
+	booked, err := client.CreateBooking(ctx, &gotravelv1.CreateBookingRequest{
+		FlightOffers: priced.GetFlightOffers(),
+		Travelers:    []*gotravelv1.Traveler{&traveler},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if booked.GetOrderId() == "" {
+		t.Fatal("no order ID")
+	}
+	if len(s.bookings.list) != 1 {
+		t.Errorf("%d bookings saved, want 1", len(s.bookings.list))
+	}
+
+	order, err := client.GetBooking(ctx, &gotravelv1.GetBookingRequest{OrderId: booked.GetOrderId()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := order.GetOrder(); got.GetId() != booked.GetOrderId() || got.GetTravelers()[0].GetName().GetLastName() != "ROJAS" {
+		t.Errorf("order = %v", got)
+	}
+
+	if _, err := client.CancelBooking(ctx, &gotravelv1.CancelBookingRequest{OrderId: booked.GetOrderId()}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.GetBooking(ctx, &gotravelv1.GetBookingRequest{OrderId: booked.GetOrderId()})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("cancelled order: got %v, want NotFound", err)
+	}
+}
+
+func TestGRPCErrorCodes(t *testing.T) {
+	s := newTestServer(t)
+	client := s.newGRPCClient()
+
+	_, _, err := searchAll(t, client, &gotravelv1.SearchFlightsRequest{Origin: "SCL", Destination: "LIM", Sort: "cheapest"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("invalid sort: got %v, want InvalidArgument", err)
+	}
+	if s.amadeus.count("GET", "/v2/shopping/flight-offers") != 0 {
+		t.Error("invalid search was sent to Amadeus")
+	}
+
+	_, _, err = searchAll(t, client, &gotravelv1.SearchFlightsRequest{Origin: "SCL", Destination: "LIM", DepartureDate: "2026-12-01"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("no adults: got %v, want InvalidArgument", err)
+	}
+	if s.amadeus.count("GET", "/v2/shopping/flight-offers") != 0 {
+		t.Error("search without adults was sent to Amadeus")
+	}
+
+	_, _, err = searchAll(t, client, &gotravelv1.SearchFlightsRequest{Origin: "SANTIAGO", Destination: "LIM", DepartureDate: "2026-12-01", Adults: 1})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("rejected by Amadeus: got %v, want InvalidArgument", err)
+	}
+
+	s.amadeus.fail("GET", "/v2/shopping/flight-offers", http.StatusInternalServerError, amadeusErrors(500, 141, "SYSTEM ERROR HAS OCCURRED", ""))
+	_, _, err = searchAll(t, client, &gotravelv1.SearchFlightsRequest{Origin: "SCL", Destination: "LIM", DepartureDate: "2026-12-01", Adults: 1})
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("Amadeus failing: got %v, want Unavailable", err)
+	}
+
+	_, err = client.CancelBooking(context.Background(), &gotravelv1.CancelBookingRequest{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("no order ID: got %v, want InvalidArgument", err)
+	}
+}
+
+func TestGRPCMetrics(t *testing.T) {
+	s := newTestServer(t)
+	client := s.newGRPCClient()
+
+	if _, err := client.CancelBooking(context.Background(), &gotravelv1.CancelBookingRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("no order ID: got %v, want InvalidArgument", err)
+	}
+	if _, _, err := searchAll(t, client, &gotravelv1.SearchFlightsRequest{Origin: "SCL", Destination: "LIM", DepartureDate: "2026-12-01", Adults: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	s.handler.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	for _, series := range []string{
+		`gotravel_grpc_requests_total{code="InvalidArgument",method="/gotravel.v1.FlightsService/CancelBooking"}`,
+		`gotravel_grpc_requests_total{code="OK",method="/gotravel.v1.FlightsService/SearchFlights"}`,
+		`gotravel_grpc_request_duration_seconds_count{method="/gotravel.v1.FlightsService/SearchFlights"}`,
+	} {
+		if !strings.Contains(rec.Body.String(), series) {
+			t.Errorf("metrics have no %s", series)
+		}
+	}
+}
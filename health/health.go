@@ -0,0 +1,145 @@
+// Package health implements the readiness checks behind /readyz.
+//
+// Checks run concurrently and their results are cached for TTL, so frequent
+// probes from load balancers do not turn into a stream of token requests to
+// the provider.
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Statuses.
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+)
+
+var errDraining = errors.New("server is shutting down")
+
+// Check is one dependency.
+type Check struct {
+	Name string
+	Func func(ctx context.Context) error
+}
+
+// Result is the outcome of a check.
+type Result struct {
+	Status    string    `json:"status"`
+	LatencyMs int64     `json:"latencyMs"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// Report is the readiness of the server and of each dependency.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Ready reports whether every check passed.
+func (r Report) Ready() bool {
+	return r.Status == StatusOK
+}
+
+// Checker runs checks and caches the report.
+type Checker struct {
+	Checks  []Check
+	TTL     time.Duration
+	Timeout time.Duration
+
+	draining atomic.Bool
+
+	mu      sync.Mutex
+	report  Report
+	expires time.Time
+	running chan struct{} // closed when the checks in flight finish
+}
+
+// Drain makes every later report unavailable, so load balancers stop sending
+// traffic while the server shuts down.
+func (c *Checker) Drain() {
+	c.draining.Store(true)
+}
+
+// Report returns the cached report, running the checks again if it expired.
+// Probes arriving while the checks run wait for their result.
+func (c *Checker) Report(ctx context.Context) Report {
+	if c.draining.Load() {
+		return drained()
+	}
+	c.mu.Lock()
+	if time.Now().Before(c.expires) {
+		defer c.mu.Unlock()
+		return c.report
+	}
+	running := c.running
+	if running == nil {
+		running = make(chan struct{})
+		c.running = running
+		c.mu.Unlock()
+		report := c.run(ctx)
+		c.mu.Lock()
+		c.report, c.expires, c.running = report, time.Now().Add(c.TTL), nil
+		close(running)
+	} else {
+		c.mu.Unlock()
+		<-running
+		c.mu.Lock()
+	}
+	report := c.report
+	c.mu.Unlock()
+
+	if c.draining.Load() {
+		return drained()
+	}
+	return report
+}
+
+func drained() Report {
+	return Report{Status: StatusUnavailable, Checks: map[string]Result{
+		"server": {Status: StatusUnavailable, Error: errDraining.Error(), CheckedAt: time.Now().UTC()},
+	}}
+}
+
+// run runs every check concurrently.
+func (c *Checker) run(ctx context.Context) Report {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = 3 * time.Second
+	}
+	// Checks must not be cut short by the probe that triggered them, since
+	// their result is shared with the following probes.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	defer cancel()
+
+	results := make([]Result, len(c.Checks))
+	var wg sync.WaitGroup
+	for i, check := range c.Checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			start := time.Now()
+			err := check.Func(ctx)
+			results[i] = Result{Status: StatusOK, LatencyMs: time.Since(start).Milliseconds(), CheckedAt: start.UTC()}
+			if err != nil {
+				results[i].Status = StatusUnavailable
+				results[i].Error = err.Error()
+			}
+		}(i, check)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]Result, len(c.Checks))}
+	for i, check := range c.Checks {
+		report.Checks[check.Name] = results[i]
+		if results[i].Status != StatusOK {
+			report.Status = StatusUnavailable
+		}
+	}
+	return report
+}
@@ -0,0 +1,123 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// counted returns a check that counts its runs and fails with err.
+func counted(name string, runs *atomic.Int32, err error) Check {
+	return Check{Name: name, Func: func(context.Context) error {
+		runs.Add(1)
+		return err
+	}}
+}
+
+func TestReportCaches(t *testing.T) {
+	var runs atomic.Int32
+	c := &Checker{Checks: []Check{counted("mongo", &runs, nil)}, TTL: 50 * time.Millisecond}
+	for i := 0; i < 3; i++ {
+		if r := c.Report(context.Background()); !r.Ready() || r.Checks["mongo"].Status != StatusOK {
+			t.Fatalf("report %d: %+v", i, r)
+		}
+	}
+	if runs.Load() != 1 {
+		t.Errorf("checks ran %d times within the TTL, want 1", runs.Load())
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	c.Report(context.Background())
+	if runs.Load() != 2 {
+		t.Errorf("checks ran %d times after the TTL, want 2", runs.Load())
+	}
+}
+
+func TestReportSharesRunningChecks(t *testing.T) {
+	var runs atomic.Int32
+	release := make(chan struct{})
+	c := &Checker{Checks: []Check{{Name: "slow", Func: func(context.Context) error {
+		runs.Add(1)
+		<-release
+		return nil
+	}}}, TTL: time.Hour}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if r := c.Report(context.Background()); !r.Ready() {
+				t.Errorf("report %+v", r)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if runs.Load() != 1 {
+		t.Errorf("concurrent probes ran the checks %d times, want 1", runs.Load())
+	}
+}
+
+func TestReportFailures(t *testing.T) {
+	var runs atomic.Int32
+	c := &Checker{Checks: []Check{
+		counted("mongo", &runs, nil),
+		counted("amadeus", &runs, errors.New("amadeus responded 503 Service Unavailable")),
+		{Name: "token", Func: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}},
+	}, Timeout: 20 * time.Millisecond}
+
+	r := c.Report(context.Background())
+	if r.Ready() || r.Status != StatusUnavailable {
+		t.Errorf("status %s with failing checks", r.Status)
+	}
+	if got := r.Checks["mongo"]; got.Status != StatusOK || got.Error != "" {
+		t.Errorf("mongo: %+v", got)
+	}
+	if got := r.Checks["amadeus"]; got.Status != StatusUnavailable || got.Error != "amadeus responded 503 Service Unavailable" {
+		t.Errorf("amadeus: %+v", got)
+	}
+	if got := r.Checks["token"]; got.Status != StatusUnavailable || got.Error != context.DeadlineExceeded.Error() {
+		t.Errorf("token: %+v, want it cut short by the timeout", got)
+	}
+}
+
+func TestDrain(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	c := &Checker{Checks: []Check{{Name: "slow", Func: func(context.Context) error {
+		close(started)
+		<-release
+		return nil
+	}}}, TTL: time.Hour}
+
+	probe := make(chan Report)
+	go func() { probe <- c.Report(context.Background()) }()
+	<-started
+
+	// Draining does not wait for the checks in flight.
+	drainedAt := make(chan struct{})
+	go func() {
+		c.Drain()
+		close(drainedAt)
+	}()
+	select {
+	case <-drainedAt:
+	case <-time.After(time.Second):
+		t.Fatal("Drain blocked while the checks ran")
+	}
+	if r := c.Report(context.Background()); r.Ready() || r.Checks["server"].Error != errDraining.Error() {
+		t.Errorf("report while draining: %+v", r)
+	}
+
+	close(release)
+	if r := <-probe; r.Ready() {
+		t.Errorf("a probe that started before Drain reported ready: %+v", r)
+	}
+}
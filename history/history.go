@@ -0,0 +1,118 @@
+// Package history records an append-only timeline of what happened to each
+// booking: its state transitions (priced, created, cancelled) and the outcome
+// of every upstream call made for it. Events are never updated or deleted, so
+// support can rely on the timeline when auditing disputes.
+package history
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go-quickstart/ids"
+)
+
+// Event types. The first three are state transitions; upstream calls do not
+// change the state of the booking.
+const (
+	TypePriced       = "priced"
+	TypeCreated      = "created"
+	TypeCancelled    = "cancelled"
+	TypeUpstreamCall = "upstream_call"
+)
+
+// Event is one entry of a booking timeline.
+type Event struct {
+	ID         string         `json:"id" bson:"_id"`
+	OrderID    string         `json:"orderId" bson:"orderId"`
+	Type       string         `json:"type" bson:"type"`
+	Operation  string         `json:"operation,omitempty" bson:"operation,omitempty"`
+	HTTPStatus int            `json:"httpStatus,omitempty" bson:"httpStatus,omitempty"`
+	Error      string         `json:"error,omitempty" bson:"error,omitempty"`
+	Details    map[string]any `json:"details,omitempty" bson:"details,omitempty"`
+	At         time.Time      `json:"at" bson:"at"`
+}
+
+// Transition reports whether the event changes the state of the booking.
+func (e Event) Transition() bool {
+	return e.Type == TypePriced || e.Type == TypeCreated || e.Type == TypeCancelled
+}
+
+// Store appends events and reads them back in order.
+type Store interface {
+	Append(ctx context.Context, e Event) error
+	Timeline(ctx context.Context, orderID string) ([]Event, error)
+}
+
+// Timeline is the history of one booking.
+type Timeline struct {
+	OrderID string  `json:"orderId"`
+	Status  string  `json:"status"`
+	Events  []Event `json:"events"`
+}
+
+// Recorder appends events to a store.
+type Recorder struct {
+	Store Store
+}
+
+// Record stamps and appends an event. Recording must never break the request
+// that triggered it, so errors are only logged. Events are read back by order
+// ID, so an event without one, such as a create that failed before the
+// provider assigned an ID, is logged instead of stored.
+func (r Recorder) Record(ctx context.Context, e Event) {
+	if e.OrderID == "" {
+		slog.WarnContext(ctx, "booking event without an order ID not recorded",
+			"type", e.Type, "operation", e.Operation, "httpStatus", e.HTTPStatus, "error", e.Error)
+		return
+	}
+	e.ID = ids.New()
+	e.At = time.Now().UTC()
+	if err := r.Store.Append(ctx, e); err != nil {
+		slog.ErrorContext(ctx, "recording booking event failed", "orderId", e.OrderID, "type", e.Type, "error", err)
+	}
+}
+
+// UpstreamCall records the outcome of a call to the flight provider that
+// creates a booking.
+func (r Recorder) UpstreamCall(ctx context.Context, orderID, operation string, status int, err error) {
+	e := Event{OrderID: orderID, Type: TypeUpstreamCall, Operation: operation, HTTPStatus: status}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	r.Record(ctx, e)
+}
+
+// OrderCall records the outcome of a call about an existing booking, such as
+// retrieving or cancelling it. Anyone can ask for any order ID, so the call is
+// only recorded when the booking's creation already is; otherwise made-up IDs
+// would get a timeline of their own.
+func (r Recorder) OrderCall(ctx context.Context, orderID, operation string, status int, err error) {
+	events, lookupErr := r.Store.Timeline(ctx, orderID)
+	if lookupErr != nil {
+		slog.ErrorContext(ctx, "reading booking events failed", "orderId", orderID, "error", lookupErr)
+		return
+	}
+	for _, e := range events {
+		if e.Type == TypeCreated {
+			r.UpstreamCall(ctx, orderID, operation, status, err)
+			return
+		}
+	}
+}
+
+// Timeline returns the events of a booking, oldest first, with the status
+// given by its last state transition.
+func (r Recorder) Timeline(ctx context.Context, orderID string) (Timeline, error) {
+	events, err := r.Store.Timeline(ctx, orderID)
+	if err != nil {
+		return Timeline{}, err
+	}
+	timeline := Timeline{OrderID: orderID, Events: events}
+	for _, e := range events {
+		if e.Transition() {
+			timeline.Status = e.Type
+		}
+	}
+	return timeline, nil
+}
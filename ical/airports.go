@@ -0,0 +1,123 @@
+package ical
+
+import "time"
+
+// airportZones maps IATA airport codes to IANA time zones. It covers the
+// airports served by the carriers we search (LATAM, JetSMART, Sky) and the
+// main long-haul hubs they connect to.
+var airportZones = map[string]string{
+	// Chile
+	"SCL": "America/Santiago",
+	"ANF": "America/Santiago",
+	"ARI": "America/Santiago",
+	"IQQ": "America/Santiago",
+	"CJC": "America/Santiago",
+	"CPO": "America/Santiago",
+	"LSC": "America/Santiago",
+	"CCP": "America/Santiago",
+	"ZCO": "America/Santiago",
+	"ZOS": "America/Santiago",
+	"PMC": "America/Santiago",
+	"BBA": "America/Santiago",
+	"PUQ": "America/Punta_Arenas",
+	"IPC": "Pacific/Easter",
+	// Peru
+	"LIM": "America/Lima",
+	"CUZ": "America/Lima",
+	"AQP": "America/Lima",
+	"PIU": "America/Lima",
+	"TRU": "America/Lima",
+	"IQT": "America/Lima",
+	// Argentina
+	"EZE": "America/Argentina/Buenos_Aires",
+	"AEP": "America/Argentina/Buenos_Aires",
+	"COR": "America/Argentina/Cordoba",
+	"MDZ": "America/Argentina/Mendoza",
+	"BRC": "America/Argentina/Salta",
+	"IGR": "America/Argentina/Cordoba",
+	// Brazil
+	"GRU": "America/Sao_Paulo",
+	"CGH": "America/Sao_Paulo",
+	"GIG": "America/Sao_Paulo",
+	"BSB": "America/Sao_Paulo",
+	"FLN": "America/Sao_Paulo",
+	"POA": "America/Sao_Paulo",
+	"SSA": "America/Bahia",
+	"REC": "America/Recife",
+	"FOR": "America/Fortaleza",
+	"MAO": "America/Manaus",
+	// Rest of South America
+	"BOG": "America/Bogota",
+	"MDE": "America/Bogota",
+	"CTG": "America/Bogota",
+	"UIO": "America/Guayaquil",
+	"GYE": "America/Guayaquil",
+	"MVD": "America/Montevideo",
+	"ASU": "America/Asuncion",
+	"VVI": "America/La_Paz",
+	"LPB": "America/La_Paz",
+	"CCS": "America/Caracas",
+	// Central America, Caribbean and Mexico
+	"PTY": "America/Panama",
+	"SJO": "America/Costa_Rica",
+	"MEX": "America/Mexico_City",
+	"CUN": "America/Cancun",
+	"PUJ": "America/Santo_Domingo",
+	"HAV": "America/Havana",
+	// North America
+	"MIA": "America/New_York",
+	"JFK": "America/New_York",
+	"EWR": "America/New_York",
+	"BOS": "America/New_York",
+	"ATL": "America/New_York",
+	"MCO": "America/New_York",
+	"IAD": "America/New_York",
+	"ORD": "America/Chicago",
+	"DFW": "America/Chicago",
+	"IAH": "America/Chicago",
+	"DEN": "America/Denver",
+	"LAX": "America/Los_Angeles",
+	"SFO": "America/Los_Angeles",
+	"YYZ": "America/Toronto",
+	// Europe
+	"MAD": "Europe/Madrid",
+	"BCN": "Europe/Madrid",
+	"LIS": "Europe/Lisbon",
+	"CDG": "Europe/Paris",
+	"FRA": "Europe/Berlin",
+	"MUC": "Europe/Berlin",
+	"AMS": "Europe/Amsterdam",
+	"FCO": "Europe/Rome",
+	"MXP": "Europe/Rome",
+	"LHR": "Europe/London",
+	"ZRH": "Europe/Zurich",
+	"IST": "Europe/Istanbul",
+	// Asia and Oceania
+	"SYD": "Australia/Sydney",
+	"MEL": "Australia/Melbourne",
+	"AKL": "Pacific/Auckland",
+	"PPT": "Pacific/Tahiti",
+	"BKK": "Asia/Bangkok",
+	"DXB": "Asia/Dubai",
+	"DOH": "Asia/Qatar",
+	"NRT": "Asia/Tokyo",
+	"HND": "Asia/Tokyo",
+	"ICN": "Asia/Seoul",
+	"SIN": "Asia/Singapore",
+	"HKG": "Asia/Hong_Kong",
+	"TLV": "Asia/Jerusalem",
+	"JNB": "Africa/Johannesburg",
+}
+
+// AirportLocation returns the time zone of an airport, if known.
+func AirportLocation(iata string) (*time.Location, bool) {
+	name, ok := airportZones[iata]
+	if !ok {
+		return nil, false
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, false
+	}
+	return loc, true
+}
@@ -0,0 +1,119 @@
+// Package ical writes iCalendar (RFC 5545) files with one event per flight.
+//
+// Amadeus gives departure and arrival times in the local time of each
+// airport, without offset. Events are written in UTC after resolving the
+// airport's time zone; airports missing from the table are written as
+// floating local times, which calendars show as-is. RFC 5545 does not allow
+// an event to mix the two, so when either end of a flight is floating both
+// are written as local times.
+package ical
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	_ "time/tzdata" // airport zones must resolve even without system tzdata
+)
+
+// Event is a calendar entry for one flight segment.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	Start       time.Time
+	End         time.Time
+}
+
+// Calendar is a list of events.
+type Calendar struct {
+	Name   string
+	Events []Event
+}
+
+// LocalTime parses an Amadeus local date-time ("2023-11-01T10:40:00") at an
+// airport. The second result is false when the airport's zone is unknown, in
+// which case the time is returned as floating (time.Local is never used).
+func LocalTime(iata, at string) (time.Time, bool, error) {
+	loc, ok := AirportLocation(iata)
+	if !ok {
+		loc = floating
+	}
+	t, err := time.ParseInLocation("2006-01-02T15:04:05", at, loc)
+	return t, ok, err
+}
+
+// floating marks times whose zone is unknown.
+var floating = time.FixedZone("floating", 0)
+
+// Write renders the calendar.
+func (c Calendar) Write(w io.Writer) error {
+	lw := &lineWriter{w: w}
+	lw.line("BEGIN:VCALENDAR")
+	lw.line("VERSION:2.0")
+	lw.line("PRODID:-//goTravel//Itinerary//ES")
+	lw.line("CALSCALE:GREGORIAN")
+	lw.line("METHOD:PUBLISH")
+	if c.Name != "" {
+		lw.line("X-WR-CALNAME:" + escape(c.Name))
+	}
+	stamp := time.Now().UTC().Format("20060102T150405Z")
+	for _, e := range c.Events {
+		lw.line("BEGIN:VEVENT")
+		lw.line("UID:" + escape(e.UID))
+		lw.line("DTSTAMP:" + stamp)
+		local := e.Start.Location() == floating || e.End.Location() == floating
+		lw.line("DTSTART:" + formatTime(e.Start, local))
+		lw.line("DTEND:" + formatTime(e.End, local))
+		lw.line("SUMMARY:" + escape(e.Summary))
+		if e.Location != "" {
+			lw.line("LOCATION:" + escape(e.Location))
+		}
+		if e.Description != "" {
+			lw.line("DESCRIPTION:" + escape(e.Description))
+		}
+		lw.line("END:VEVENT")
+	}
+	lw.line("END:VCALENDAR")
+	return lw.err
+}
+
+// formatTime writes t in UTC, or as the wall clock time at its airport when
+// local is set.
+func formatTime(t time.Time, local bool) string {
+	if local {
+		return t.Format("20060102T150405")
+	}
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// escape escapes TEXT values as required by RFC 5545 section 3.3.11.
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// lineWriter writes CRLF-terminated content lines folded at 75 octets.
+type lineWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (lw *lineWriter) line(s string) {
+	if lw.err != nil {
+		return
+	}
+	var b strings.Builder
+	width := 0
+	for _, r := range s {
+		n := len(string(r))
+		if width+n > 75 {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += n
+	}
+	b.WriteString("\r\n")
+	_, lw.err = fmt.Fprint(lw.w, b.String())
+}
@@ -0,0 +1,18 @@
+// Package ids generates the identifiers of the documents the server stores,
+// such as watches, webhook deliveries and booking events.
+package ids
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// New returns 12 random bytes in hex, as long as a MongoDB ObjectID but
+// not guessable from the time or from other identifiers.
+func New() string {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		panic("ids: reading random bytes: " + err.Error())
+	}
+	return hex.EncodeToString(b)
+}
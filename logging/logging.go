@@ -0,0 +1,284 @@
+// Package logging sets up structured JSON logging with request IDs and
+// redaction of traveler data.
+//
+// Attributes whose key names personal data (names, emails, phones, dates of
+// birth, document numbers) or credentials (Authorization, cookies) are
+// replaced with "[REDACTED]", at any depth of a logged struct or map, so a
+// logged http.Header or gRPC metadata.MD is safe. Email addresses, phone-like
+// numbers, passport numbers and bearer tokens are also masked inside
+// free-form strings such as error messages, since Amadeus echoes request
+// fields back in its errors.
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDHeader carries the request ID in requests and responses.
+const RequestIDHeader = "X-Request-ID"
+
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are compared lowercased and without separators.
+var sensitiveKeys = map[string]bool{
+	"name":           true,
+	"firstname":      true,
+	"lastname":       true,
+	"middlename":     true,
+	"travelers":      true,
+	"email":          true,
+	"emailaddress":   true,
+	"phone":          true,
+	"phones":         true,
+	"phonenumber":    true,
+	"number":         true,
+	"dateofbirth":    true,
+	"dob":            true,
+	"birthdate":      true,
+	"birthplace":     true,
+	"passport":       true,
+	"passportnumber": true,
+	"documentnumber": true,
+	"documents":      true,
+	"contact":        true,
+	"to":             true,
+	"recipients":     true,
+	"authorization":  true,
+	"cookie":         true,
+	"setcookie":      true,
+	"accesstoken":    true,
+	"clientsecret":   true,
+}
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	// Phone numbers with a country prefix, or long runs of digits. Dates
+	// and prices are left alone.
+	phonePattern = regexp.MustCompile(`\+\d[\d\s-]{6,}\d|\b\d{9,}\b`)
+	// Passport numbers: one or two letters and six to eight digits. Flight
+	// numbers have at most four digits.
+	passportPattern = regexp.MustCompile(`\b[A-Z]{1,2}\d{6,8}\b`)
+	bearerPattern   = regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9._~+/=-]+`)
+)
+
+// ParseLevel parses "debug", "info", "warn" or "error", defaulting to info.
+func ParseLevel(s string) slog.Level {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(s))); err != nil {
+		return slog.LevelInfo
+	}
+	return level
+}
+
+// New returns a JSON logger that redacts personal data and adds the request
+// ID and trace of the context passed to the *Context logging methods.
+func New(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(contextHandler{slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redact,
+	})})
+}
+
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String("requestId", id))
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		r.AddAttrs(slog.String("traceId", span.TraceID().String()), slog.String("spanId", span.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+func redact(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey || a.Key == slog.SourceKey) {
+		return a
+	}
+	if sensitive(a.Key) {
+		return slog.String(a.Key, redacted)
+	}
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, Scrub(a.Value.String()))
+	case slog.KindAny:
+		switch v := a.Value.Any().(type) {
+		case error:
+			return slog.String(a.Key, Scrub(v.Error()))
+		case json.RawMessage:
+			return slog.Any(a.Key, redactJSON(v))
+		default:
+			raw, err := json.Marshal(v)
+			if err != nil {
+				return slog.String(a.Key, redacted)
+			}
+			return slog.Any(a.Key, redactJSON(raw))
+		}
+	}
+	return a
+}
+
+// redactJSON decodes a JSON document and redacts it; values that cannot be
+// decoded are dropped rather than logged as-is.
+func redactJSON(raw []byte) any {
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return redacted
+	}
+	return redactValue(v)
+}
+
+func redactValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if sensitive(key) {
+				v[key] = redacted
+			} else {
+				v[key] = redactValue(value)
+			}
+		}
+		return v
+	case []any:
+		for i := range v {
+			v[i] = redactValue(v[i])
+		}
+		return v
+	case string:
+		return Scrub(v)
+	}
+	return v
+}
+
+func sensitive(key string) bool {
+	key = strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(key))
+	return sensitiveKeys[key]
+}
+
+// Scrub masks email addresses, phone-like numbers, passport numbers and
+// Authorization credentials in s.
+func Scrub(s string) string {
+	s = bearerPattern.ReplaceAllString(s, "$1 "+redacted)
+	s = emailPattern.ReplaceAllString(s, redacted)
+	s = passportPattern.ReplaceAllString(s, redacted)
+	return phonePattern.ReplaceAllString(s, redacted)
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a context carrying the request ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID in ctx, if any.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// Middleware assigns every request an ID, taken from the X-Request-ID header
+// when the caller sends a sane one, returns it in the response and logs the
+// request once it is done. The path is logged without its query string.
+func Middleware(c *gin.Context) {
+	start := time.Now()
+	id := c.GetHeader(RequestIDHeader)
+	if !validRequestID(id) {
+		id = newRequestID()
+	}
+	c.Header(RequestIDHeader, id)
+	c.Request = c.Request.WithContext(WithRequestID(c.Request.Context(), id))
+
+	c.Next()
+
+	status := c.Writer.Status()
+	level := slog.LevelInfo
+	switch {
+	case status >= 500:
+		level = slog.LevelError
+	case status >= 400:
+		level = slog.LevelWarn
+	}
+	slog.Log(c.Request.Context(), level, "request",
+		"method", c.Request.Method,
+		"route", c.FullPath(),
+		"path", c.Request.URL.Path,
+		"status", status,
+		"durationMs", time.Since(start).Milliseconds(),
+		"bytes", c.Writer.Size(),
+	)
+}
+
+// Recovery turns panics into a 500 and logs them, instead of gin's default
+// recovery which dumps the raw request.
+func Recovery(c *gin.Context) {
+	defer func() {
+		if err := recover(); err != nil {
+			slog.ErrorContext(c.Request.Context(), "panic serving request",
+				"route", c.FullPath(),
+				"error", fmt.Sprint(err),
+				"stack", string(debug.Stack()))
+			c.AbortWithStatus(http.StatusInternalServerError)
+		}
+	}()
+	c.Next()
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Transport adds the request ID of the request context to outgoing calls.
+type Transport struct {
+	Base http.RoundTripper
+}
+
+func (t Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if id := RequestID(req.Context()); id != "" && req.Header.Get(RequestIDHeader) == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set(RequestIDHeader, id)
+	}
+	return base.RoundTrip(req)
+}
@@ -0,0 +1,116 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Traveler data as it shows up in Amadeus errors and request headers.
+const (
+	email    = "ana.rojas@example.com"
+	phone    = "+56 9 1234 5678"
+	passport = "AB1234567"
+	token    = "dGhpc2lzYXRva2Vu.c2VjcmV0"
+)
+
+var leak = "traveler " + email + ", phone " + phone + ", passport " + passport + ", Authorization: Bearer " + token
+
+// capture sends the default logger to a buffer for the rest of the test.
+func capture(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	saved := slog.Default()
+	slog.SetDefault(New(&buf, slog.LevelDebug))
+	t.Cleanup(func() { slog.SetDefault(saved) })
+	return &buf
+}
+
+func assertMasked(t *testing.T, logs string) {
+	t.Helper()
+	if logs == "" {
+		t.Fatal("nothing was logged")
+	}
+	for _, secret := range []string{email, phone, "1234 5678", passport, token} {
+		if strings.Contains(logs, secret) {
+			t.Errorf("log contains %q:\n%s", secret, logs)
+		}
+	}
+	if !strings.Contains(logs, redacted) {
+		t.Errorf("log has no %s marker:\n%s", redacted, logs)
+	}
+}
+
+func TestScrub(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"write to " + email, "write to " + redacted},
+		{"call " + phone + " now", "call " + redacted + " now"},
+		{"call 912345678", "call " + redacted},
+		{"passport " + passport + " expired", "passport " + redacted + " expired"},
+		{"Authorization: Bearer " + token, "Authorization: Bearer " + redacted},
+		{"basic " + token, "basic " + redacted},
+		// Flight numbers, dates and prices are not personal data.
+		{"LA443 on 2026-12-01 for 180000.00 CLP", "LA443 on 2026-12-01 for 180000.00 CLP"},
+	}
+	for _, tt := range tests {
+		if got := Scrub(tt.in); got != tt.want {
+			t.Errorf("Scrub(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestHTTPLogsAreMasked(t *testing.T) {
+	logs := capture(t)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Middleware, Recovery)
+	router.GET("/log", func(c *gin.Context) {
+		slog.WarnContext(c.Request.Context(), "booking rejected",
+			"error", leak,
+			"headers", c.Request.Header,
+			"traveler", map[string]any{"passportNumber": passport, "contact": map[string]string{"emailAddress": email}})
+		c.Status(http.StatusBadRequest)
+	})
+	router.GET("/panic", func(c *gin.Context) { panic(leak) })
+
+	for _, path := range []string{"/log", "/panic"} {
+		req := httptest.NewRequest("GET", path+"?email="+email, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	assertMasked(t, logs.String())
+}
+
+func TestGRPCLogsAreMasked(t *testing.T) {
+	logs := capture(t)
+	md := metadata.Pairs("authorization", "Bearer "+token)
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+	info := &grpc.UnaryServerInfo{FullMethod: "/gotravel.v1.Booking/Book"}
+
+	fail := func(ctx context.Context, req any) (any, error) {
+		incoming, _ := metadata.FromIncomingContext(ctx)
+		slog.InfoContext(ctx, "booking", "metadata", incoming)
+		return nil, status.Error(codes.InvalidArgument, leak)
+	}
+	crash := func(ctx context.Context, req any) (any, error) { panic(leak) }
+
+	for _, handler := range []grpc.UnaryHandler{fail, crash} {
+		if _, err := UnaryInterceptor(ctx, nil, info, handler); err == nil {
+			t.Error("error was swallowed")
+		}
+	}
+	assertMasked(t, logs.String())
+	if !strings.Contains(logs.String(), `"code":"InvalidArgument"`) {
+		t.Errorf("call was not logged:\n%s", logs)
+	}
+}
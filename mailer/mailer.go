@@ -0,0 +1,247 @@
+// Package mailer renders booking confirmation emails and sends them over SMTP.
+//
+// Templates live in templates/ and are embedded in the binary, one HTML and
+// one plain-text version per language. Any SMTP server works as a target,
+// including local stand-ins such as MailHog or smtp4dev (SMTP_HOST=localhost,
+// SMTP_PORT=1025, no credentials) for checking the emails by hand. The tests
+// send to an in-process SMTP listener.
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+//go:embed templates
+var templates embed.FS
+
+// Languages with templates. The first one is the default.
+var Languages = []string{"es", "en"}
+
+var subjects = map[string]string{
+	"es": "Confirmación de reserva %s",
+	"en": "Booking confirmation %s",
+}
+
+var ErrNoRecipients = errors.New("mailer: no recipients")
+
+// Segment is one flight of the itinerary, already formatted for display.
+type Segment struct {
+	Flight    string
+	From      string
+	To        string
+	Departure string
+	Arrival   string
+	Terminal  string
+}
+
+// Confirmation is the data rendered in a booking confirmation.
+type Confirmation struct {
+	OrderID   string
+	Travelers []string
+	Segments  []Segment
+	Price     string
+}
+
+// Message is a rendered email.
+type Message struct {
+	Subject string
+	HTML    string
+	Text    string
+}
+
+// Language picks a supported language from a tag or an Accept-Language
+// header ("en-US,en;q=0.9"), falling back to Spanish.
+func Language(tag string) string {
+	for _, part := range strings.Split(tag, ",") {
+		lang, _, _ := strings.Cut(strings.TrimSpace(part), ";")
+		lang, _, _ = strings.Cut(strings.ToLower(lang), "-")
+		for _, supported := range Languages {
+			if lang == supported {
+				return lang
+			}
+		}
+	}
+	return Languages[0]
+}
+
+// Render renders the confirmation in the given language.
+func Render(lang string, data Confirmation) (Message, error) {
+	lang = Language(lang)
+	html, err := htmltemplate.ParseFS(templates, "templates/confirmation."+lang+".html")
+	if err != nil {
+		return Message{}, err
+	}
+	text, err := texttemplate.ParseFS(templates, "templates/confirmation."+lang+".txt")
+	if err != nil {
+		return Message{}, err
+	}
+
+	var htmlBody, textBody bytes.Buffer
+	if err := html.Execute(&htmlBody, data); err != nil {
+		return Message{}, err
+	}
+	if err := text.Execute(&textBody, data); err != nil {
+		return Message{}, err
+	}
+	return Message{
+		Subject: fmt.Sprintf(subjects[lang], data.OrderID),
+		HTML:    htmlBody.String(),
+		Text:    textBody.String(),
+	}, nil
+}
+
+// FormatTime turns an Amadeus local date-time ("2023-11-01T10:40:00") into
+// a readable one for the given language, or returns it unchanged.
+func FormatTime(lang, at string) string {
+	t, err := time.Parse("2006-01-02T15:04:05", at)
+	if err != nil {
+		return at
+	}
+	if Language(lang) == "en" {
+		return t.Format("Jan 2, 2006 15:04")
+	}
+	return t.Format("02-01-2006 15:04")
+}
+
+// Sender delivers messages.
+type Sender interface {
+	Send(ctx context.Context, to []string, msg Message) error
+}
+
+// SMTPSender sends messages through an SMTP server. Authentication is only
+// used when Username is set; STARTTLS is used when the server offers it.
+type SMTPSender struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// Send builds a multipart/alternative message with the text and HTML bodies
+// and hands it to the server. The connection is dialed with ctx and closed
+// when ctx is done, so a server that stops answering cannot hold it open.
+func (s SMTPSender) Send(ctx context.Context, to []string, msg Message) error {
+	if len(to) == 0 {
+		return ErrNoRecipients
+	}
+	body, err := s.build(to, msg)
+	if err != nil {
+		return err
+	}
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", net.JoinHostPort(s.Host, s.Port))
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	// Unblocks reads and writes as soon as ctx is cancelled.
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	err = s.send(conn, to, body)
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	// The only deadline on conn is ctx's, which may fire before ctx notices.
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		return context.DeadlineExceeded
+	}
+	return err
+}
+
+// send runs the SMTP conversation of smtp.SendMail over conn, and closes it.
+func (s SMTPSender) send(conn net.Conn, to []string, body []byte) error {
+	c, err := smtp.NewClient(conn, s.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: s.Host}); err != nil {
+			return err
+		}
+	}
+	if s.Username != "" {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New("mailer: server does not support authentication")
+		}
+		if err := c.Auth(smtp.PlainAuth("", s.Username, s.Password, s.Host)); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(s.From); err != nil {
+		return err
+	}
+	for _, addr := range to {
+		if err := c.Rcpt(addr); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+func (s SMTPSender) build(to []string, msg Message) ([]byte, error) {
+	boundary := randomBoundary()
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", s.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&b, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", boundary)
+
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain", msg.Text},
+		{"text/html", msg.HTML},
+	} {
+		fmt.Fprintf(&b, "--%s\r\n", boundary)
+		fmt.Fprintf(&b, "Content-Type: %s; charset=utf-8\r\n", part.contentType)
+		b.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+		w := quotedprintable.NewWriter(&b)
+		if _, err := w.Write([]byte(part.body)); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		b.WriteString("\r\n")
+	}
+	fmt.Fprintf(&b, "--%s--\r\n", boundary)
+	return b.Bytes(), nil
+}
+
+func randomBoundary() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	return "gotravel-" + hex.EncodeToString(buf)
+}
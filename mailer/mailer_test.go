@@ -0,0 +1,214 @@
+package mailer
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+)
+
+// received is a message accepted by smtpServer.
+type received struct {
+	From string
+	To   []string
+	Data string
+}
+
+// smtpServer accepts one message on a local port, speaking just enough SMTP
+// for net/smtp: no extensions, so no STARTTLS and no AUTH.
+func smtpServer(t *testing.T) (addr string, messages <-chan received) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { lis.Close() })
+	ch := make(chan received, 1)
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+		r := bufio.NewReader(conn)
+		reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+		var msg received
+		reply("220 localhost ESMTP test")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+			switch {
+			case verb == "EHLO" || verb == "HELO":
+				reply("250 localhost")
+			case strings.HasPrefix(strings.ToUpper(line), "MAIL FROM:"):
+				msg.From = strings.Trim(line[len("MAIL FROM:"):], "<>")
+				reply("250 OK")
+			case strings.HasPrefix(strings.ToUpper(line), "RCPT TO:"):
+				msg.To = append(msg.To, strings.Trim(line[len("RCPT TO:"):], "<>"))
+				reply("250 OK")
+			case verb == "DATA":
+				reply("354 end with <CRLF>.<CRLF>")
+				var data strings.Builder
+				for {
+					l, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if l == ".\r\n" {
+						break
+					}
+					data.WriteString(strings.TrimPrefix(l, "."))
+				}
+				msg.Data = data.String()
+				reply("250 OK")
+				ch <- msg
+			case verb == "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+	return lis.Addr().String(), ch
+}
+
+func TestSendConfirmation(t *testing.T) {
+	addr, messages := smtpServer(t)
+	host, port, _ := net.SplitHostPort(addr)
+
+	msg, err := Render("es-CL,es;q=0.9", Confirmation{
+		OrderID:   "eJzTd9cP",
+		Travelers: []string{"JORGE GONZALES"},
+		Segments: []Segment{
+			{Flight: "LA 600", From: "SCL", To: "LIM", Departure: FormatTime("es", "2026-12-01T08:00:00"), Arrival: "2026-12-01T10:30:00", Terminal: "2"},
+		},
+		Price: "$250.000 CLP",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := SMTPSender{Host: host, Port: port, From: "reservas@gotravel.local"}
+	if err := sender.Send(context.Background(), []string{"jorge@example.com"}, msg); err != nil {
+		t.Fatal(err)
+	}
+
+	var got received
+	select {
+	case got = <-messages:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message received")
+	}
+	if got.From != "reservas@gotravel.local" || len(got.To) != 1 || got.To[0] != "jorge@example.com" {
+		t.Errorf("envelope from %q to %q, want reservas@gotravel.local to jorge@example.com", got.From, got.To)
+	}
+
+	parsed, err := mail.ReadMessage(strings.NewReader(got.Data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject != "Confirmación de reserva eJzTd9cP" {
+		t.Errorf("subject %q", subject)
+	}
+	if to := parsed.Header.Get("To"); to != "jorge@example.com" {
+		t.Errorf("To header %q", to)
+	}
+
+	_, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := map[string]string{}
+	mr := multipart.NewReader(parsed.Body, params["boundary"])
+	for {
+		part, err := mr.NextRawPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(quotedprintable.NewReader(part))
+		if err != nil {
+			t.Fatal(err)
+		}
+		mediaType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		parts[mediaType] = string(body)
+	}
+	for _, want := range []string{
+		"Código de reserva: eJzTd9cP",
+		"LA 600  SCL -> LIM (T2)  salida 01-12-2026 08:00",
+		"  - JORGE GONZALES",
+		"Precio total: $250.000 CLP",
+	} {
+		if !strings.Contains(parts["text/plain"], want) {
+			t.Errorf("text body lacks %q:\n%s", want, parts["text/plain"])
+		}
+	}
+	for _, want := range []string{
+		"<strong>eJzTd9cP</strong>",
+		"<td>LA 600</td><td>SCL</td><td>LIM (T2)</td>",
+		"<li>JORGE GONZALES</li>",
+	} {
+		if !strings.Contains(parts["text/html"], want) {
+			t.Errorf("HTML body lacks %q:\n%s", want, parts["text/html"])
+		}
+	}
+}
+
+func TestSendWithoutRecipients(t *testing.T) {
+	if err := (SMTPSender{Host: "127.0.0.1", Port: "25"}).Send(context.Background(), nil, Message{}); err != ErrNoRecipients {
+		t.Errorf("err = %v, want ErrNoRecipients", err)
+	}
+}
+
+func TestSendGivesUpWithContext(t *testing.T) {
+	// The server accepts the connection and never greets.
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+	closed := make(chan struct{})
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		io.Copy(io.Discard, conn)
+		close(closed)
+	}()
+
+	host, port, _ := net.SplitHostPort(lis.Addr().String())
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err = (SMTPSender{Host: host, Port: port}).Send(ctx, []string{"jorge@example.com"}, Message{Subject: "hola"})
+	if !errors.Is(err, context.DeadlineExceeded) || time.Since(start) > time.Second {
+		t.Fatalf("err = %v after %v, want DeadlineExceeded at the deadline", err, time.Since(start))
+	}
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Error("the connection was left open")
+	}
+}
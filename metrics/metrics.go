@@ -0,0 +1,140 @@
+// Package metrics defines the Prometheus metrics of the API and the helpers
+// that record them. Everything is registered in the default registry and
+// exposed by Handler.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+var (
+	requests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gotravel_http_requests_total",
+		Help: "API requests by route, method and status code.",
+	}, []string{"route", "method", "status"})
+
+	requestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "gotravel_http_request_duration_seconds",
+		Help:    "API request latency by route and method.",
+		Buckets: []float64{.01, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"route", "method"})
+
+	upstreamDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "gotravel_upstream_request_duration_seconds",
+		Help:    "Latency of every attempt to call a provider, by operation.",
+		Buckets: []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 20},
+	}, []string{"provider", "operation"})
+
+	upstreamErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gotravel_upstream_errors_total",
+		Help: "Failed provider calls by operation and reason (status code, timeout, network or circuit_open).",
+	}, []string{"provider", "operation", "reason"})
+
+	tokenRefreshes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gotravel_token_refreshes_total",
+		Help: "Access token requests by result.",
+	}, []string{"provider", "result"})
+
+	mongoDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "gotravel_mongo_operation_duration_seconds",
+		Help:    "MongoDB command latency by command and result.",
+		Buckets: []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 5},
+	}, []string{"command", "result"})
+
+	bookingsCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "gotravel_bookings_created_total",
+		Help: "Bookings created.",
+	})
+
+	bookingsCancelled = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "gotravel_bookings_cancelled_total",
+		Help: "Bookings cancelled.",
+	})
+)
+
+// Handler serves the metrics in the Prometheus text format.
+func Handler() gin.HandlerFunc {
+	return gin.WrapH(promhttp.Handler())
+}
+
+// Middleware records the count and latency of every request. Requests are
+// labeled with the route pattern ("/api/booking/:id") rather than the path,
+// so IDs do not create new series.
+func Middleware(c *gin.Context) {
+	start := time.Now()
+	c.Next()
+	route := c.FullPath()
+	if route == "" {
+		route = "unmatched"
+	}
+	requests.WithLabelValues(route, c.Request.Method, strconv.Itoa(c.Writer.Status())).Inc()
+	requestDuration.WithLabelValues(route, c.Request.Method).Observe(time.Since(start).Seconds())
+}
+
+// Upstream records one attempt to call a provider. status is 0 when no
+// response was received.
+func Upstream(provider, operation string, status int, err error, elapsed time.Duration) {
+	if operation == "" {
+		operation = "unknown"
+	}
+	upstreamDuration.WithLabelValues(provider, operation).Observe(elapsed.Seconds())
+	switch {
+	case err != nil:
+		upstreamErrors.WithLabelValues(provider, operation, errorReason(err)).Inc()
+	case status >= 400:
+		upstreamErrors.WithLabelValues(provider, operation, strconv.Itoa(status)).Inc()
+	}
+}
+
+// CircuitOpen counts a call rejected by the circuit breaker.
+func CircuitOpen(provider, operation string) {
+	upstreamErrors.WithLabelValues(provider, operation, "circuit_open").Inc()
+}
+
+func errorReason(err error) string {
+	var timeout interface{ Timeout() bool }
+	if errors.As(err, &timeout) && timeout.Timeout() || errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+	if errors.Is(err, context.Canceled) {
+		return "canceled"
+	}
+	return "network"
+}
+
+// TokenRefresh counts an access token request.
+func TokenRefresh(provider string, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	tokenRefreshes.WithLabelValues(provider, result).Inc()
+}
+
+// BookingCreated counts a new booking.
+func BookingCreated() { bookingsCreated.Inc() }
+
+// BookingCancelled counts a cancelled booking.
+func BookingCancelled() { bookingsCancelled.Inc() }
+
+// MongoMonitor times every MongoDB command sent by a client configured with
+// it (options.Client().SetMonitor).
+func MongoMonitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Succeeded: func(_ context.Context, e *event.CommandSucceededEvent) {
+			mongoDuration.WithLabelValues(e.CommandName, "success").Observe(e.Duration.Seconds())
+		},
+		Failed: func(_ context.Context, e *event.CommandFailedEvent) {
+			mongoDuration.WithLabelValues(e.CommandName, "error").Observe(e.Duration.Seconds())
+		},
+	}
+}
@@ -0,0 +1,296 @@
+// Package money implements a decimal amount tied to an ISO 4217 currency code.
+//
+// Amadeus returns every price as a string ("546.70"), which cannot be summed,
+// compared or sorted. Money parses those strings into a fixed-point value so
+// the server and the CLI can do arithmetic on them without float rounding.
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// scale is the number of fractional digits kept internally. It is larger than
+// any currency exponent so converted amounts keep precision until formatting.
+const scale = 4
+
+var factor = pow10(scale)
+
+var (
+	ErrInvalidAmount    = errors.New("money: invalid amount")
+	ErrCurrencyMismatch = errors.New("money: currency mismatch")
+)
+
+// exponents lists currencies whose minor unit is not the usual two digits.
+var exponents = map[string]int{
+	"CLP": 0,
+	"JPY": 0,
+	"KRW": 0,
+	"PYG": 0,
+	"ISK": 0,
+	"BHD": 3,
+	"KWD": 3,
+	"OMR": 3,
+}
+
+// Money is an amount in a given currency. The zero value is a zero amount
+// without currency.
+type Money struct {
+	units    int64 // amount * 10^scale
+	currency string
+}
+
+// Parse reads an Amadeus style decimal string such as "546.70" or "-12".
+func Parse(amount, currency string) (Money, error) {
+	s := strings.TrimSpace(amount)
+	if s == "" {
+		return Money{}, fmt.Errorf("%w: empty string", ErrInvalidAmount)
+	}
+	neg := false
+	switch s[0] {
+	case '-':
+		neg = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	if intPart == "" && fracPart == "" {
+		return Money{}, fmt.Errorf("%w: %q has no digits", ErrInvalidAmount, amount)
+	}
+	if intPart == "" {
+		intPart = "0"
+	}
+	if len(fracPart) > scale || !digits(intPart) || !digits(fracPart) {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, amount)
+	}
+	frac := int64(0)
+	if fracPart != "" {
+		frac, _ = strconv.ParseInt(fracPart, 10, 64)
+		frac *= pow10(scale - len(fracPart))
+	}
+	whole, err := strconv.ParseInt(intPart, 10, 64)
+	if err != nil || whole > (math.MaxInt64-frac)/factor {
+		return Money{}, fmt.Errorf("%w: %q is too large", ErrInvalidAmount, amount)
+	}
+	units := whole*factor + frac
+	if neg {
+		units = -units
+	}
+	return Money{units: units, currency: strings.ToUpper(currency)}, nil
+}
+
+// Currency returns the ISO 4217 code of m.
+func (m Money) Currency() string { return m.currency }
+
+// IsZero reports whether the amount is zero.
+func (m Money) IsZero() bool { return m.units == 0 }
+
+// Add returns m + o. Both amounts must share the same currency; a zero value
+// without currency is accepted on either side so sums can start from Money{}.
+func (m Money) Add(o Money) (Money, error) {
+	cur, err := sameCurrency(m, o)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{units: m.units + o.units, currency: cur}, nil
+}
+
+// Sub returns m - o.
+func (m Money) Sub(o Money) (Money, error) {
+	cur, err := sameCurrency(m, o)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{units: m.units - o.units, currency: cur}, nil
+}
+
+// Exchange converts m into another currency by multiplying it by rate, the
+// number of target currency units per unit of m's currency. The result keeps
+// the internal precision; rounding to the minor unit happens when formatting.
+func (m Money) Exchange(rate *big.Rat, to string) Money {
+	v := new(big.Rat).Mul(new(big.Rat).SetInt64(m.units), rate)
+	num, den := v.Num(), v.Denom()
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	// Round half away from zero.
+	if r.Sign() != 0 && new(big.Int).Mul(new(big.Int).Abs(r), big.NewInt(2)).Cmp(den) >= 0 {
+		q.Add(q, big.NewInt(int64(num.Sign())))
+	}
+	return Money{units: q.Int64(), currency: strings.ToUpper(to)}
+}
+
+// Cmp compares m and o and returns -1, 0 or +1.
+func (m Money) Cmp(o Money) (int, error) {
+	if _, err := sameCurrency(m, o); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.units < o.units:
+		return -1, nil
+	case m.units > o.units:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Sum adds all amounts. It returns a zero Money for an empty list.
+func Sum(amounts ...Money) (Money, error) {
+	var total Money
+	for _, a := range amounts {
+		var err error
+		if total, err = total.Add(a); err != nil {
+			return Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// Float64 returns an approximation of the amount, for scoring and ratios only.
+func (m Money) Float64() float64 {
+	return float64(m.units) / float64(factor)
+}
+
+// Amount returns the decimal amount rounded to the currency's minor unit,
+// in the same format Amadeus uses ("546.70", "125000").
+func (m Money) Amount() string {
+	exp := Exponent(m.currency)
+	units := m.rounded(exp)
+	neg := units < 0
+	if neg {
+		units = -units
+	}
+	whole := strconv.FormatInt(units/pow10(exp), 10)
+	out := whole
+	if exp > 0 {
+		out += "." + fmt.Sprintf("%0*d", exp, units%pow10(exp))
+	}
+	if neg {
+		out = "-" + out
+	}
+	return out
+}
+
+// String returns the amount followed by its currency, e.g. "546.70 EUR".
+func (m Money) String() string {
+	if m.currency == "" {
+		return m.Amount()
+	}
+	return m.Amount() + " " + m.currency
+}
+
+// Format renders m for display with thousand separators. Spanish locales
+// ("es", "es-CL", ...) use "." for thousands and "," for decimals; everything
+// else uses the English convention. CLP and other zero-exponent currencies
+// are printed without decimals.
+func (m Money) Format(locale string) string {
+	thousands, decimal := ",", "."
+	if strings.HasPrefix(strings.ToLower(locale), "es") {
+		thousands, decimal = ".", ","
+	}
+	amount := m.Amount()
+	neg := strings.HasPrefix(amount, "-")
+	amount = strings.TrimPrefix(amount, "-")
+	whole, frac, _ := strings.Cut(amount, ".")
+
+	var b strings.Builder
+	if m.currency != "" {
+		b.WriteString(m.currency + " ")
+	}
+	if neg {
+		b.WriteByte('-')
+	}
+	for i, r := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteString(thousands)
+		}
+		b.WriteRune(r)
+	}
+	if frac != "" {
+		b.WriteString(decimal + frac)
+	}
+	return b.String()
+}
+
+// MarshalJSON encodes m as {"amount":"546.70","currency":"EUR"}.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount   string `json:"amount"`
+		Currency string `json:"currency"`
+	}{m.Amount(), m.currency})
+}
+
+// UnmarshalJSON decodes the format written by MarshalJSON.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var v struct {
+		Amount   string `json:"amount"`
+		Currency string `json:"currency"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	parsed, err := Parse(v.Amount, v.Currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Exponent returns the number of decimals used by a currency's minor unit.
+func Exponent(currency string) int {
+	if exp, ok := exponents[strings.ToUpper(currency)]; ok {
+		return exp
+	}
+	return 2
+}
+
+// rounded returns the amount in units of 10^-exp, rounding half away from zero.
+func (m Money) rounded(exp int) int64 {
+	div := pow10(scale - exp)
+	q, r := m.units/div, m.units%div
+	if r < 0 {
+		r = -r
+	}
+	if 2*r >= div {
+		if m.units < 0 {
+			q--
+		} else {
+			q++
+		}
+	}
+	return q
+}
+
+func sameCurrency(a, b Money) (string, error) {
+	switch {
+	case a.currency == b.currency:
+		return a.currency, nil
+	case a.currency == "" && a.units == 0:
+		return b.currency, nil
+	case b.currency == "" && b.units == 0:
+		return a.currency, nil
+	}
+	return "", fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, a.currency, b.currency)
+}
+
+func digits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func pow10(n int) int64 {
+	p := int64(1)
+	for i := 0; i < n; i++ {
+		p *= 10
+	}
+	return p
+}
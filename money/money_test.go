@@ -0,0 +1,158 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		amount string
+		units  int64
+	}{
+		{"546.70", 5467000},
+		{"125000", 1250000000},
+		{"-12", -120000},
+		{"+3.5", 35000},
+		{".25", 2500},
+		{"7.", 70000},
+		{" 0.0001 ", 1},
+		{"0012", 120000},
+		{"922337203685477.5807", 1<<63 - 1},
+	}
+	for _, tt := range tests {
+		m, err := Parse(tt.amount, "eur")
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.amount, err)
+			continue
+		}
+		if m.units != tt.units || m.Currency() != "EUR" {
+			t.Errorf("Parse(%q) = %d %s, want %d EUR", tt.amount, m.units, m.Currency(), tt.units)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, amount := range []string{
+		"", " ", ".", "-", "+", "-.", "1.2.3", "1,000.00", "12a", "1e3", "--1",
+		"0.00001",                // more decimals than the internal scale
+		"922337203685477.5808",   // one unit over the maximum
+		"922337203685478",        // whole part over the maximum
+		"99999999999999999999",   // not even an int64
+		"-922337203685477.58080", // too many decimals and too large
+	} {
+		if m, err := Parse(amount, "USD"); !errors.Is(err, ErrInvalidAmount) {
+			t.Errorf("Parse(%q) = %v, %v; want ErrInvalidAmount", amount, m, err)
+		}
+	}
+}
+
+func TestExchangeRoundsAtScale(t *testing.T) {
+	third := big.NewRat(1, 3)
+	half := big.NewRat(1, 2)
+	tests := []struct {
+		units int64
+		rate  *big.Rat
+		want  int64
+	}{
+		{10000, third, 3333}, // 0.33333 -> 0.3333
+		{20000, third, 6667}, // 0.66666 -> 0.6667
+		{1, half, 1},         // 0.00005 rounds half away from zero
+		{-1, half, -1},       // and so does -0.00005
+		{3, half, 2},         // 0.00015 -> 0.0002
+		{1250000000, big.NewRat(107, 100000), 1337500}, // 125000 CLP at 0.00107
+	}
+	for _, tt := range tests {
+		got := Money{units: tt.units, currency: "CLP"}.Exchange(tt.rate, "usd")
+		if got.units != tt.want || got.Currency() != "USD" {
+			t.Errorf("%d * %s = %d %s, want %d USD", tt.units, tt.rate, got.units, got.Currency(), tt.want)
+		}
+	}
+}
+
+func TestAmountRoundsToMinorUnit(t *testing.T) {
+	tests := []struct {
+		amount, currency, want string
+	}{
+		{"546.7", "EUR", "546.70"},
+		{"0.005", "USD", "0.01"},
+		{"-0.005", "USD", "-0.01"},
+		{"0.0049", "USD", "0.00"},
+		{"125000.5", "CLP", "125001"},
+		{"1.2345", "KWD", "1.235"},
+	}
+	for _, tt := range tests {
+		m, err := Parse(tt.amount, tt.currency)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := m.Amount(); got != tt.want {
+			t.Errorf("Parse(%q, %s).Amount() = %q, want %q", tt.amount, tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestMixedCurrencies(t *testing.T) {
+	usd, _ := Parse("10", "USD")
+	eur, _ := Parse("10", "EUR")
+	if _, err := usd.Add(eur); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Add: err = %v, want ErrCurrencyMismatch", err)
+	}
+	if _, err := usd.Sub(eur); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Sub: err = %v, want ErrCurrencyMismatch", err)
+	}
+	if _, err := usd.Cmp(eur); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Cmp: err = %v, want ErrCurrencyMismatch", err)
+	}
+	if _, err := Sum(usd, usd, eur); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Sum: err = %v, want ErrCurrencyMismatch", err)
+	}
+
+	// The zero value takes the currency of the other side.
+	total, err := Money{}.Add(usd)
+	if err != nil || total.String() != "10.00 USD" {
+		t.Errorf("Money{}.Add(usd) = %v, %v; want 10.00 USD", total, err)
+	}
+	if cmp, err := usd.Cmp(Money{}); err != nil || cmp != 1 {
+		t.Errorf("usd.Cmp(Money{}) = %d, %v; want 1", cmp, err)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	clp, _ := Parse("1250000", "CLP")
+	usd, _ := Parse("-1234.5", "USD")
+	for _, tt := range []struct {
+		m      Money
+		locale string
+		want   string
+	}{
+		{clp, "es-CL", "CLP 1.250.000"},
+		{clp, "en", "CLP 1,250,000"},
+		{usd, "es", "USD -1.234,50"},
+		{usd, "en-US", "USD -1,234.50"},
+	} {
+		if got := tt.m.Format(tt.locale); got != tt.want {
+			t.Errorf("%v.Format(%q) = %q, want %q", tt.m, tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	m, _ := Parse("546.7", "EUR")
+	raw, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(raw) != `{"amount":"546.70","currency":"EUR"}` {
+		t.Errorf("Marshal = %s", raw)
+	}
+	var decoded Money
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded != m {
+		t.Errorf("Unmarshal = %v, want %v", decoded, m)
+	}
+}
@@ -0,0 +1,301 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: gotravel/v1/gotravel.proto
+
+// The gRPC API of goTravel. It offers the same operations as the REST API
+// under /api, backed by the same code.
+//
+// Offer, itinerary and traveler messages follow the Amadeus Self-Service
+// documents field by field, and their JSON names match Amadeus', so offers
+// returned by SearchFlights can be sent back unchanged to PriceOffers and
+// CreateBooking.
+
+package gotravelv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	FlightsService_SearchFlights_FullMethodName = "/gotravel.v1.FlightsService/SearchFlights"
+	FlightsService_PriceOffers_FullMethodName   = "/gotravel.v1.FlightsService/PriceOffers"
+	FlightsService_CreateBooking_FullMethodName = "/gotravel.v1.FlightsService/CreateBooking"
+	FlightsService_GetBooking_FullMethodName    = "/gotravel.v1.FlightsService/GetBooking"
+	FlightsService_CancelBooking_FullMethodName = "/gotravel.v1.FlightsService/CancelBooking"
+)
+
+// FlightsServiceClient is the client API for FlightsService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type FlightsServiceClient interface {
+	// SearchFlights streams a SearchMeta followed by every offer of the
+	// requested page, sorted and scored as in GET /api/search.
+	SearchFlights(ctx context.Context, in *SearchFlightsRequest, opts ...grpc.CallOption) (FlightsService_SearchFlightsClient, error)
+	// PriceOffers confirms the price of offers returned by SearchFlights.
+	PriceOffers(ctx context.Context, in *PriceOffersRequest, opts ...grpc.CallOption) (*PriceOffersResponse, error)
+	// CreateBooking books priced offers and emails the confirmation.
+	CreateBooking(ctx context.Context, in *CreateBookingRequest, opts ...grpc.CallOption) (*CreateBookingResponse, error)
+	GetBooking(ctx context.Context, in *GetBookingRequest, opts ...grpc.CallOption) (*GetBookingResponse, error)
+	CancelBooking(ctx context.Context, in *CancelBookingRequest, opts ...grpc.CallOption) (*CancelBookingResponse, error)
+}
+
+type flightsServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFlightsServiceClient(cc grpc.ClientConnInterface) FlightsServiceClient {
+	return &flightsServiceClient{cc}
+}
+
+func (c *flightsServiceClient) SearchFlights(ctx context.Context, in *SearchFlightsRequest, opts ...grpc.CallOption) (FlightsService_SearchFlightsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &FlightsService_ServiceDesc.Streams[0], FlightsService_SearchFlights_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &flightsServiceSearchFlightsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FlightsService_SearchFlightsClient interface {
+	Recv() (*SearchFlightsResponse, error)
+	grpc.ClientStream
+}
+
+type flightsServiceSearchFlightsClient struct {
+	grpc.ClientStream
+}
+
+func (x *flightsServiceSearchFlightsClient) Recv() (*SearchFlightsResponse, error) {
+	m := new(SearchFlightsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *flightsServiceClient) PriceOffers(ctx context.Context, in *PriceOffersRequest, opts ...grpc.CallOption) (*PriceOffersResponse, error) {
+	out := new(PriceOffersResponse)
+	err := c.cc.Invoke(ctx, FlightsService_PriceOffers_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *flightsServiceClient) CreateBooking(ctx context.Context, in *CreateBookingRequest, opts ...grpc.CallOption) (*CreateBookingResponse, error) {
+	out := new(CreateBookingResponse)
+	err := c.cc.Invoke(ctx, FlightsService_CreateBooking_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *flightsServiceClient) GetBooking(ctx context.Context, in *GetBookingRequest, opts ...grpc.CallOption) (*GetBookingResponse, error) {
+	out := new(GetBookingResponse)
+	err := c.cc.Invoke(ctx, FlightsService_GetBooking_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *flightsServiceClient) CancelBooking(ctx context.Context, in *CancelBookingRequest, opts ...grpc.CallOption) (*CancelBookingResponse, error) {
+	out := new(CancelBookingResponse)
+	err := c.cc.Invoke(ctx, FlightsService_CancelBooking_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FlightsServiceServer is the server API for FlightsService service.
+// All implementations must embed UnimplementedFlightsServiceServer
+// for forward compatibility
+type FlightsServiceServer interface {
+	// SearchFlights streams a SearchMeta followed by every offer of the
+	// requested page, sorted and scored as in GET /api/search.
+	SearchFlights(*SearchFlightsRequest, FlightsService_SearchFlightsServer) error
+	// PriceOffers confirms the price of offers returned by SearchFlights.
+	PriceOffers(context.Context, *PriceOffersRequest) (*PriceOffersResponse, error)
+	// CreateBooking books priced offers and emails the confirmation.
+	CreateBooking(context.Context, *CreateBookingRequest) (*CreateBookingResponse, error)
+	GetBooking(context.Context, *GetBookingRequest) (*GetBookingResponse, error)
+	CancelBooking(context.Context, *CancelBookingRequest) (*CancelBookingResponse, error)
+	mustEmbedUnimplementedFlightsServiceServer()
+}
+
+// UnimplementedFlightsServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedFlightsServiceServer struct {
+}
+
+func (UnimplementedFlightsServiceServer) SearchFlights(*SearchFlightsRequest, FlightsService_SearchFlightsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SearchFlights not implemented")
+}
+func (UnimplementedFlightsServiceServer) PriceOffers(context.Context, *PriceOffersRequest) (*PriceOffersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PriceOffers not implemented")
+}
+func (UnimplementedFlightsServiceServer) CreateBooking(context.Context, *CreateBookingRequest) (*CreateBookingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateBooking not implemented")
+}
+func (UnimplementedFlightsServiceServer) GetBooking(context.Context, *GetBookingRequest) (*GetBookingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBooking not implemented")
+}
+func (UnimplementedFlightsServiceServer) CancelBooking(context.Context, *CancelBookingRequest) (*CancelBookingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelBooking not implemented")
+}
+func (UnimplementedFlightsServiceServer) mustEmbedUnimplementedFlightsServiceServer() {}
+
+// UnsafeFlightsServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FlightsServiceServer will
+// result in compilation errors.
+type UnsafeFlightsServiceServer interface {
+	mustEmbedUnimplementedFlightsServiceServer()
+}
+
+func RegisterFlightsServiceServer(s grpc.ServiceRegistrar, srv FlightsServiceServer) {
+	s.RegisterService(&FlightsService_ServiceDesc, srv)
+}
+
+func _FlightsService_SearchFlights_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SearchFlightsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FlightsServiceServer).SearchFlights(m, &flightsServiceSearchFlightsServer{stream})
+}
+
+type FlightsService_SearchFlightsServer interface {
+	Send(*SearchFlightsResponse) error
+	grpc.ServerStream
+}
+
+type flightsServiceSearchFlightsServer struct {
+	grpc.ServerStream
+}
+
+func (x *flightsServiceSearchFlightsServer) Send(m *SearchFlightsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _FlightsService_PriceOffers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PriceOffersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlightsServiceServer).PriceOffers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FlightsService_PriceOffers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlightsServiceServer).PriceOffers(ctx, req.(*PriceOffersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FlightsService_CreateBooking_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateBookingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlightsServiceServer).CreateBooking(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FlightsService_CreateBooking_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlightsServiceServer).CreateBooking(ctx, req.(*CreateBookingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FlightsService_GetBooking_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBookingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlightsServiceServer).GetBooking(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FlightsService_GetBooking_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlightsServiceServer).GetBooking(ctx, req.(*GetBookingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FlightsService_CancelBooking_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelBookingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlightsServiceServer).CancelBooking(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FlightsService_CancelBooking_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlightsServiceServer).CancelBooking(ctx, req.(*CancelBookingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// FlightsService_ServiceDesc is the grpc.ServiceDesc for FlightsService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FlightsService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gotravel.v1.FlightsService",
+	HandlerType: (*FlightsServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "PriceOffers",
+			Handler:    _FlightsService_PriceOffers_Handler,
+		},
+		{
+			MethodName: "CreateBooking",
+			Handler:    _FlightsService_CreateBooking_Handler,
+		},
+		{
+			MethodName: "GetBooking",
+			Handler:    _FlightsService_GetBooking_Handler,
+		},
+		{
+			MethodName: "CancelBooking",
+			Handler:    _FlightsService_CancelBooking_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SearchFlights",
+			Handler:       _FlightsService_SearchFlights_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "gotravel/v1/gotravel.proto",
+}
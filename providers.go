@@ -0,0 +1,348 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go-quickstart/config"
+	"go-quickstart/search"
+	"go-quickstart/upstream"
+)
+
+// flightProvider is a source of flight offers that can also price and book
+// them. Every provider speaks the Amadeus documents the API is built on:
+// adapters of other APIs translate them. Order IDs are the provider's own;
+// see orderRef.
+type flightProvider interface {
+	Search(ctx context.Context, params searchParams) (FlighOffers, error)
+	Price(ctx context.Context, searchPrice FlightPriceRequest, withFareRules bool) (PricingResponse, error)
+	Book(ctx context.Context, bookingRequest BookingRequest) (BookingResponse, error)
+	Order(ctx context.Context, orderID string) (OrderResponse, error)
+	Cancel(ctx context.Context, orderID string) error
+}
+
+// providers are the known flight providers. cfg.Search.Providers picks the
+// ones searched; offers and orders go back to the one they came from.
+var providers = map[string]flightProvider{
+	config.ProviderAmadeus: amadeusProvider{},
+	config.ProviderDuffel:  duffelProvider{},
+}
+
+// offersProvider returns the provider of a set of offers, given the provider
+// recorded on each of them. Offers without one come from Amadeus, as before
+// providers were recorded. Offers of different providers cannot be priced or
+// booked together.
+func offersProvider(names []string) (string, flightProvider, error) {
+	name := ""
+	for _, n := range names {
+		if n == "" {
+			n = config.ProviderAmadeus
+		}
+		if name != "" && n != name {
+			return "", nil, invalidInput(fmt.Errorf("offers from %s and %s cannot be priced or booked together", name, n))
+		}
+		name = n
+	}
+	if name == "" {
+		name = config.ProviderAmadeus
+	}
+	provider, ok := providers[name]
+	if !ok {
+		return "", nil, invalidInput(fmt.Errorf("unknown flight provider %q", name))
+	}
+	return name, provider, nil
+}
+
+// orderRef is the order ID returned to clients for an order of provider:
+// Amadeus IDs as they are, so existing orders keep theirs, and the ID of
+// other providers prefixed with the provider name, as in "duffel:ord_123".
+// A missing ID stays empty.
+func orderRef(provider, orderID string) string {
+	if provider == config.ProviderAmadeus || orderID == "" {
+		return orderID
+	}
+	return provider + ":" + orderID
+}
+
+// orderProvider splits an order ID made by orderRef into its provider and
+// the provider's order ID.
+func orderProvider(ref string) (flightProvider, string) {
+	if name, orderID, ok := strings.Cut(ref, ":"); ok {
+		if provider, ok := providers[name]; ok && name != config.ProviderAmadeus {
+			return provider, orderID
+		}
+	}
+	return providers[config.ProviderAmadeus], ref
+}
+
+// searchFlights searches every configured provider at once and merges their
+// offers, each one marked with its provider. An itinerary offered by several
+// providers is only kept from the one selling it cheapest, with all of its
+// fares for it. A provider that fails is left out of the results, unless
+// every provider fails.
+func searchFlights(ctx context.Context, params searchParams) (FlighOffers, error) {
+	names := cfg.Search.Providers
+	results := make([]FlighOffers, len(names))
+	errs := make([]error, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			provider, ok := providers[name]
+			if !ok {
+				errs[i] = fmt.Errorf("unknown flight provider %q", name)
+				return
+			}
+			results[i], errs[i] = provider.Search(ctx, params)
+		}(i, name)
+	}
+	wg.Wait()
+
+	var merged *FlighOffers
+	foreign := false
+	for i, name := range names {
+		if errs[i] != nil {
+			if len(names) > 1 {
+				slog.WarnContext(ctx, "flight provider search failed", "provider", name, "error", errs[i])
+			}
+			continue
+		}
+		for j := range results[i].Data {
+			results[i].Data[j].Provider = name
+			foreign = foreign || results[i].Data[j].Price.Currency != cfg.Search.Currency
+		}
+		if merged == nil {
+			merged = &results[i]
+		}
+	}
+	if merged == nil {
+		return FlighOffers{}, errs[0]
+	}
+	if len(names) == 1 {
+		return *merged, nil
+	}
+	// Prices in other currencies are compared in the billing one.
+	if foreign {
+		if err := ensureRates(ctx); err != nil {
+			slog.WarnContext(ctx, "loading exchange rates failed", "error", err)
+		}
+	}
+
+	// The provider selling each itinerary cheapest, by index.
+	keys := make([][]string, len(names))
+	seller := map[string]int{}
+	cheapest := map[string]search.Offer{}
+	for i := range names {
+		if errs[i] != nil {
+			continue
+		}
+		summaries := summarizeOffers(results[i])
+		keys[i] = make([]string, len(results[i].Data))
+		for j, summary := range summaries {
+			key := itineraryKey(results[i], j)
+			keys[i][j] = key
+			// An offer whose price could not be read never beats one whose
+			// price could.
+			prev, found := cheapest[key]
+			cheaper := !found || prev.Incomplete && !summary.Incomplete
+			if !cheaper && !summary.Incomplete {
+				c, err := summary.Price.Cmp(prev.Price)
+				cheaper = err == nil && c < 0
+			}
+			if cheaper {
+				seller[key], cheapest[key] = i, summary
+			}
+		}
+	}
+
+	offers := *merged
+	offers.Data = offers.Data[:0:0]
+	for i := range names {
+		for j, offer := range results[i].Data {
+			if seller[keys[i][j]] == i {
+				offers.Data = append(offers.Data, offer)
+			}
+		}
+	}
+	return offers, nil
+}
+
+// itineraryKey identifies the flights of an offer: the carrier, number and
+// departure time of every segment.
+func itineraryKey(offers FlighOffers, i int) string {
+	var key strings.Builder
+	for _, itinerary := range offers.Data[i].Itineraries {
+		for _, segment := range itinerary.Segments {
+			fmt.Fprintf(&key, "%s%s@%s,", segment.CarrierCode, segment.Number, segment.Departure.At)
+		}
+		key.WriteString("/")
+	}
+	return key.String()
+}
+
+// amadeusProvider is the Amadeus Self-Service API, whose documents the rest
+// of the server uses as they are.
+type amadeusProvider struct{}
+
+// Search asks Amadeus for the offers matching search and fills in the
+// operating carrier of every segment.
+func (amadeusProvider) Search(ctx context.Context, search searchParams) (FlighOffers, error) {
+	var accessToken = getToken(ctx)
+	query := url.Values{
+		"originLocationCode":      {search.Origen},
+		"destinationLocationCode": {search.Destino},
+		"departureDate":           {search.FechaSalida},
+		"adults":                  {search.Adultos},
+		"includedAirlineCodes":    {strings.Join(cfg.Search.Airlines, ",")},
+		"nonStop":                 {strconv.FormatBool(cfg.Search.NonStop)},
+		"currencyCode":            {cfg.Search.Currency},
+		"travelClass":             {cfg.Search.TravelClass},
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", cfg.Amadeus.URL("/v2/shopping/flight-offers?"+query.Encode()), nil)
+	if err != nil {
+		return FlighOffers{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := amadeus.Do(upstream.WithOperation(req, "flight-offers.search"))
+	if err != nil {
+		return FlighOffers{}, err
+	}
+	defer resp.Body.Close()
+	if err := checkAmadeusResponse(resp); err != nil {
+		return FlighOffers{}, err
+	}
+
+	var flightSearchResponse FlighOffers
+	err = json.NewDecoder(resp.Body).Decode(&flightSearchResponse)
+	if err != nil {
+		return FlighOffers{}, fmt.Errorf("decoding flight search response: %w", err)
+	}
+
+	for i := range flightSearchResponse.Data {
+		for j := range flightSearchResponse.Data[i].Itineraries {
+			for k := range flightSearchResponse.Data[i].Itineraries[j].Segments {
+				if flightSearchResponse.Data[i].Itineraries[j].Segments[k].Operating.CarrierCode == "" {
+					// Si 'operating.carrierCode' está vacío, asigna el valor de 'carrierCode' de nivel superior.
+					flightSearchResponse.Data[i].Itineraries[j].Segments[k].Operating.CarrierCode = flightSearchResponse.Data[i].Itineraries[j].Segments[k].CarrierCode
+				}
+			}
+		}
+	}
+	return flightSearchResponse, nil
+}
+
+// Price confirms the price of offers, with their detailed fare rules when
+// asked.
+func (amadeusProvider) Price(ctx context.Context, searchPrice FlightPriceRequest, withFareRules bool) (PricingResponse, error) {
+	pricingURL := cfg.Amadeus.URL("/v1/shopping/flight-offers/pricing")
+	if withFareRules {
+		pricingURL += "?include=detailed-fare-rules"
+	}
+
+	// The provider is ours, not part of the Amadeus offer.
+	searchPrice.Data.FlightOffers = slices.Clone(searchPrice.Data.FlightOffers)
+	for i := range searchPrice.Data.FlightOffers {
+		searchPrice.Data.FlightOffers[i].Provider = ""
+	}
+
+	var accessToken = getToken(ctx)
+	pricingData, _ := json.Marshal(searchPrice)
+	req, err := http.NewRequestWithContext(ctx, "POST", pricingURL, bytes.NewBuffer(pricingData))
+	if err != nil {
+		return PricingResponse{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Content-Type", "application/json")
+	// Pricing does not change anything upstream, so it is safe to retry.
+	req = upstream.Idempotent(req)
+
+	resp, err := amadeus.Do(upstream.WithOperation(req, "flight-offers.pricing"))
+	if err != nil {
+		return PricingResponse{}, err
+	}
+	defer resp.Body.Close()
+	if err := checkAmadeusResponse(resp); err != nil {
+		return PricingResponse{}, err
+	}
+
+	var pricingResponse PricingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&pricingResponse); err != nil {
+		slog.ErrorContext(ctx, "decoding pricing response failed", "status", resp.StatusCode, "error", err)
+		return PricingResponse{}, fmt.Errorf("decoding pricing response: %w", err)
+	}
+	return pricingResponse, nil
+}
+
+// Book creates a flight order.
+func (amadeusProvider) Book(ctx context.Context, bookingRequest BookingRequest) (BookingResponse, error) {
+	bookingRequest.Data.FlightOffers = slices.Clone(bookingRequest.Data.FlightOffers)
+	for i := range bookingRequest.Data.FlightOffers {
+		bookingRequest.Data.FlightOffers[i].Provider = ""
+	}
+
+	var accessToken = getToken(ctx)
+	bookingData, _ := json.Marshal(bookingRequest)
+	req, err := http.NewRequestWithContext(ctx, "POST", cfg.Amadeus.URL("/v1/booking/flight-orders"), bytes.NewBuffer(bookingData))
+	if err != nil {
+		return BookingResponse{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := amadeus.Do(upstream.WithOperation(req, "flight-orders.create"))
+	if err != nil {
+		bookingHistory.UpstreamCall(ctx, "", "flight-orders.create", 0, err)
+		return BookingResponse{}, err
+	}
+	defer resp.Body.Close()
+	if err := checkAmadeusResponse(resp); err != nil {
+		bookingHistory.UpstreamCall(ctx, "", "flight-orders.create", resp.StatusCode, err)
+		return BookingResponse{}, err
+	}
+
+	var bookingResponse BookingResponse
+	err = json.NewDecoder(resp.Body).Decode(&bookingResponse)
+	bookingHistory.UpstreamCall(ctx, bookingResponse.Data.ID, "flight-orders.create", resp.StatusCode, err)
+	if err != nil {
+		slog.ErrorContext(ctx, "decoding booking response failed", "status", resp.StatusCode, "error", err)
+		return BookingResponse{}, fmt.Errorf("decoding booking response: %w", err)
+	}
+	return bookingResponse, nil
+}
+
+func (amadeusProvider) Order(ctx context.Context, orderID string) (OrderResponse, error) {
+	return getOrder(ctx, getToken(ctx), orderID)
+}
+
+// Cancel cancels a flight order. A rejected cancellation is returned as a
+// *providerError.
+func (amadeusProvider) Cancel(ctx context.Context, orderID string) error {
+	var accessToken = getToken(ctx)
+	url := cfg.Amadeus.URL("/v1/booking/flight-orders/" + orderID)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := amadeus.Do(upstream.WithOperation(req, "flight-orders.cancel"))
+	if err != nil {
+		bookingHistory.OrderCall(ctx, orderID, "flight-orders.cancel", 0, err)
+		return err
+	}
+	defer resp.Body.Close()
+	err = checkAmadeusResponse(resp)
+	bookingHistory.OrderCall(ctx, orderID, "flight-orders.cancel", resp.StatusCode, err)
+	return err
+}
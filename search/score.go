@@ -0,0 +1,164 @@
+package search
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Tags attached to the offers that stand out in a result set.
+const (
+	TagCheapest = "cheapest"
+	TagFastest  = "fastest"
+	TagBest     = "best"
+)
+
+// Weights of each criterion in the best-value score. They are normalized, so
+// they only need to be non-negative and not all zero.
+type Weights struct {
+	Price     float64 `json:"price"`
+	Duration  float64 `json:"duration"`
+	Stops     float64 `json:"stops"`
+	Departure float64 `json:"departure"`
+}
+
+// DefaultWeights favors price, then total trip duration.
+var DefaultWeights = Weights{Price: 0.5, Duration: 0.3, Stops: 0.15, Departure: 0.05}
+
+// ParseWeights reads weights written as "price=0.6,duration=0.3,stops=0.1".
+// Criteria left out get a weight of zero.
+func ParseWeights(s string) (Weights, error) {
+	var w Weights
+	for _, part := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return Weights{}, fmt.Errorf("%w: weights=%q", ErrInvalidOption, s)
+		}
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil || f < 0 {
+			return Weights{}, fmt.Errorf("%w: weights=%q", ErrInvalidOption, s)
+		}
+		switch strings.ToLower(key) {
+		case "price":
+			w.Price = f
+		case "duration":
+			w.Duration = f
+		case "stops":
+			w.Stops = f
+		case "departure":
+			w.Departure = f
+		default:
+			return Weights{}, fmt.Errorf("%w: weights=%q", ErrInvalidOption, s)
+		}
+	}
+	if w.Price+w.Duration+w.Stops+w.Departure == 0 {
+		return Weights{}, fmt.Errorf("%w: weights=%q", ErrInvalidOption, s)
+	}
+	return w, nil
+}
+
+// Scores rates an offer against the rest of the result set. Every score goes
+// from 0 (worst) to 1 (best).
+type Scores struct {
+	Price     float64 `json:"price"`
+	Duration  float64 `json:"duration"`
+	Stops     float64 `json:"stops"`
+	Departure float64 `json:"departure"`
+	Best      float64 `json:"best"`
+}
+
+// Score computes the scores of every offer and tags the cheapest, fastest and
+// best-value ones. Ties share the tag. Incomplete offers are left out: their
+// scores are zero and they get no tags.
+func Score(offers []Offer, w Weights) ([]Scores, [][]string) {
+	scores := make([]Scores, len(offers))
+	tags := make([][]string, len(offers))
+
+	var complete []int
+	for i, o := range offers {
+		if !o.Incomplete {
+			complete = append(complete, i)
+		}
+	}
+	if len(complete) == 0 {
+		return scores, tags
+	}
+
+	prices := make([]float64, len(complete))
+	durations := make([]float64, len(complete))
+	stops := make([]float64, len(complete))
+	for j, i := range complete {
+		prices[j] = offers[i].Price.Float64()
+		durations[j] = float64(offers[i].Duration)
+		stops[j] = float64(offers[i].Stops)
+	}
+	total := w.Price + w.Duration + w.Stops + w.Departure
+	if total == 0 {
+		w, total = DefaultWeights, 1
+	}
+
+	for j, i := range complete {
+		s := Scores{
+			Price:     normalize(prices, j),
+			Duration:  normalize(durations, j),
+			Stops:     normalize(stops, j),
+			Departure: departureConvenience(offers[i].Departure),
+		}
+		s.Best = round((w.Price*s.Price + w.Duration*s.Duration + w.Stops*s.Stops + w.Departure*s.Departure) / total)
+		scores[i] = s
+	}
+
+	minPrice, minDuration, maxBest := math.Inf(1), math.Inf(1), math.Inf(-1)
+	for j, i := range complete {
+		minPrice = math.Min(minPrice, prices[j])
+		minDuration = math.Min(minDuration, durations[j])
+		maxBest = math.Max(maxBest, scores[i].Best)
+	}
+	for j, i := range complete {
+		if prices[j] == minPrice {
+			tags[i] = append(tags[i], TagCheapest)
+		}
+		if durations[j] == minDuration {
+			tags[i] = append(tags[i], TagFastest)
+		}
+		if scores[i].Best == maxBest {
+			tags[i] = append(tags[i], TagBest)
+		}
+	}
+	return scores, tags
+}
+
+// normalize maps values[i] to [0, 1] where the lowest value scores 1.
+func normalize(values []float64, i int) float64 {
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo, hi = math.Min(lo, v), math.Max(hi, v)
+	}
+	if hi == lo {
+		return 1
+	}
+	return round((hi - values[i]) / (hi - lo))
+}
+
+// departureConvenience gives full marks to departures between 07:00 and
+// 21:00 and loses a fifth of the score per hour outside that window.
+func departureConvenience(t time.Time) float64 {
+	if t.IsZero() {
+		return 0
+	}
+	hour := float64(t.Hour()) + float64(t.Minute())/60
+	var off float64
+	switch {
+	case hour < 7:
+		off = 7 - hour
+	case hour > 21:
+		off = hour - 21
+	}
+	return round(math.Max(0, 1-off/5))
+}
+
+func round(f float64) float64 {
+	return math.Round(f*1000) / 1000
+}
@@ -0,0 +1,115 @@
+package search
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"go-quickstart/money"
+)
+
+func clp(t *testing.T, amount string) money.Money {
+	t.Helper()
+	m, err := money.Parse(amount, "CLP")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func at(t *testing.T, s string) time.Time {
+	t.Helper()
+	d, err := ParseTime(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+// sample returns five offers; their indices are used in the expectations.
+func sample(t *testing.T) []Offer {
+	return []Offer{
+		{Price: clp(t, "250000"), Duration: 3 * time.Hour, Departure: at(t, "2026-12-01T08:00:00"), Arrival: at(t, "2026-12-01T11:00:00"), Carriers: []string{"LA"}, BagsIncluded: true},
+		{Price: clp(t, "180000"), Duration: 5 * time.Hour, Departure: at(t, "2026-12-01T06:30:00"), Arrival: at(t, "2026-12-01T11:30:00"), Stops: 1, Carriers: []string{"H2"}},
+		{Price: clp(t, "180000"), Duration: 4 * time.Hour, Departure: at(t, "2026-12-01T14:00:00"), Arrival: at(t, "2026-12-01T18:00:00"), Carriers: []string{"JA", "LA"}},
+		{Price: clp(t, "320000"), Duration: 2*time.Hour + 30*time.Minute, Departure: at(t, "2026-12-01T21:15:00"), Arrival: at(t, "2026-12-01T23:45:00"), Carriers: []string{"LA"}, BagsIncluded: true},
+		{Duration: 2 * time.Hour, Departure: at(t, "2026-12-01T10:00:00"), Carriers: []string{"LA"}, Incomplete: true},
+	}
+}
+
+func TestScoreTags(t *testing.T) {
+	offers := sample(t)
+	scores, tags := Score(offers, DefaultWeights)
+
+	want := [][]string{
+		nil,
+		{TagCheapest},
+		{TagCheapest, TagBest},
+		{TagFastest},
+		nil, // incomplete: its zero price and short duration do not count
+	}
+	if !reflect.DeepEqual(tags, want) {
+		t.Errorf("tags %q, want %q", tags, want)
+	}
+
+	// 180000 is the lowest price and 320000 the highest.
+	for i, price := range []float64{0.5, 1, 1, 0, 0} {
+		if scores[i].Price != price {
+			t.Errorf("offer %d: price score %v, want %v", i, scores[i].Price, price)
+		}
+	}
+	if scores[4] != (Scores{}) {
+		t.Errorf("incomplete offer scored %+v", scores[4])
+	}
+	// 06:30 is half an hour before the convenient window.
+	if scores[1].Departure != 0.9 || scores[0].Departure != 1 {
+		t.Errorf("departure scores %v and %v, want 0.9 and 1", scores[1].Departure, scores[0].Departure)
+	}
+}
+
+func TestScoreWeights(t *testing.T) {
+	offers := sample(t)
+	// Only duration counts, so the fastest offer is also the best.
+	_, tags := Score(offers, Weights{Duration: 1})
+	if !reflect.DeepEqual(tags[3], []string{TagFastest, TagBest}) {
+		t.Errorf("duration only: tags of the fastest offer %q", tags[3])
+	}
+	// Zero weights fall back to the defaults.
+	_, tags = Score(offers, Weights{})
+	if !reflect.DeepEqual(tags[2], []string{TagCheapest, TagBest}) {
+		t.Errorf("zero weights: tags %q", tags[2])
+	}
+}
+
+func TestScoreEdgeCases(t *testing.T) {
+	if scores, tags := Score(nil, DefaultWeights); len(scores) != 0 || len(tags) != 0 {
+		t.Errorf("no offers: %v %v", scores, tags)
+	}
+
+	offers := []Offer{{Incomplete: true}, {Incomplete: true}}
+	if _, tags := Score(offers, DefaultWeights); tags[0] != nil || tags[1] != nil {
+		t.Errorf("only incomplete offers were tagged: %q", tags)
+	}
+
+	// Identical offers share every tag and score 1.
+	same := Offer{Price: clp(t, "100000"), Duration: time.Hour, Departure: at(t, "2026-12-01T09:00:00")}
+	scores, tags := Score([]Offer{same, same}, DefaultWeights)
+	for i := range tags {
+		if !reflect.DeepEqual(tags[i], []string{TagCheapest, TagFastest, TagBest}) || scores[i].Best != 1 {
+			t.Errorf("offer %d: tags %q, best %v", i, tags[i], scores[i].Best)
+		}
+	}
+}
+
+func TestParseWeights(t *testing.T) {
+	w, err := ParseWeights("price=0.6, Duration=0.3,stops=0.1")
+	if err != nil || w != (Weights{Price: 0.6, Duration: 0.3, Stops: 0.1}) {
+		t.Errorf("ParseWeights = %+v, %v", w, err)
+	}
+	for _, s := range []string{"", "price", "price=-1", "price=x", "comfort=1", "price=0,duration=0"} {
+		if _, err := ParseWeights(s); !errors.Is(err, ErrInvalidOption) {
+			t.Errorf("ParseWeights(%q): err = %v, want ErrInvalidOption", s, err)
+		}
+	}
+}
@@ -0,0 +1,323 @@
+// Package search sorts, filters, scores and paginates flight offers.
+//
+// It works on Offer, a flat summary of the fields that matter for ranking, so
+// it does not depend on the shape of the Amadeus response. Callers build one
+// Offer per result, call Apply and reorder their own slice with the returned
+// indices.
+package search
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-quickstart/money"
+)
+
+// DefaultLimit is the page size used when the request does not set one.
+const DefaultLimit = 50
+
+// MaxLimit caps the page size a client may ask for.
+const MaxLimit = 250
+
+var ErrInvalidOption = errors.New("search: invalid option")
+
+// Offer summarizes a flight offer.
+type Offer struct {
+	Price        money.Money
+	Duration     time.Duration // sum of the itinerary durations
+	Departure    time.Time     // departure of the first segment, local time
+	Arrival      time.Time     // arrival of the last segment, local time
+	Stops        int           // connections plus technical stops
+	Carriers     []string      // marketing and operating carriers
+	BagsIncluded bool          // every segment includes checked bags
+	// Incomplete is set when the price, the duration or the segments could
+	// not be read (or converted), so they are zero. Such offers are sorted
+	// last, fail the price and duration filters, and are neither scored nor
+	// tagged.
+	Incomplete bool
+}
+
+// Sort keys accepted by Options.Sort. Prefix with "-" for descending order.
+const (
+	SortPrice     = "price"
+	SortDuration  = "duration"
+	SortDeparture = "departure"
+	SortArrival   = "arrival"
+	SortStops     = "stops"
+)
+
+// Options are the sort, filter and pagination settings of a search request.
+type Options struct {
+	Sort       string
+	Descending bool
+
+	Carriers     []string
+	DepartAfter  time.Duration // time of day, inclusive
+	DepartBefore time.Duration // time of day, inclusive; zero means no limit
+	MaxDuration  time.Duration
+	MaxPrice     *money.Money
+	BagsIncluded bool
+
+	Offset int
+	Limit  int
+}
+
+// ParseOptions reads the options from query parameters:
+//
+//	sort=price|duration|departure|arrival|stops (prefix "-" to reverse)
+//	carrier=LA,H2          only offers flown or sold by these carriers
+//	departAfter=08:00      departure time window
+//	departBefore=14:30
+//	maxDuration=PT6H       ISO 8601 or Go duration ("6h")
+//	maxPrice=250000        in the billing currency
+//	bags=true              only offers with checked bags included
+//	limit=20&offset=40     or limit=20&cursor=<nextCursor of previous page>
+func ParseOptions(q url.Values, currency string) (Options, error) {
+	opts := Options{Limit: DefaultLimit}
+
+	if s := q.Get("sort"); s != "" {
+		opts.Descending = strings.HasPrefix(s, "-")
+		opts.Sort = strings.ToLower(strings.TrimPrefix(s, "-"))
+		switch opts.Sort {
+		case SortPrice, SortDuration, SortDeparture, SortArrival, SortStops:
+		default:
+			return Options{}, fmt.Errorf("%w: sort=%q", ErrInvalidOption, s)
+		}
+	}
+	for _, v := range q["carrier"] {
+		for _, code := range strings.Split(v, ",") {
+			if code = strings.ToUpper(strings.TrimSpace(code)); code != "" {
+				opts.Carriers = append(opts.Carriers, code)
+			}
+		}
+	}
+
+	var err error
+	if s := q.Get("departAfter"); s != "" {
+		if opts.DepartAfter, err = parseClock(s); err != nil {
+			return Options{}, fmt.Errorf("%w: departAfter=%q", ErrInvalidOption, s)
+		}
+	}
+	if s := q.Get("departBefore"); s != "" {
+		if opts.DepartBefore, err = parseClock(s); err != nil {
+			return Options{}, fmt.Errorf("%w: departBefore=%q", ErrInvalidOption, s)
+		}
+	}
+	if s := q.Get("maxDuration"); s != "" {
+		if opts.MaxDuration, err = ParseDuration(s); err != nil {
+			if opts.MaxDuration, err = time.ParseDuration(s); err != nil {
+				return Options{}, fmt.Errorf("%w: maxDuration=%q", ErrInvalidOption, s)
+			}
+		}
+	}
+	if s := q.Get("maxPrice"); s != "" {
+		maxPrice, err := money.Parse(s, currency)
+		if err != nil {
+			return Options{}, fmt.Errorf("%w: maxPrice=%q", ErrInvalidOption, s)
+		}
+		opts.MaxPrice = &maxPrice
+	}
+	if s := q.Get("bags"); s != "" {
+		if opts.BagsIncluded, err = strconv.ParseBool(s); err != nil {
+			return Options{}, fmt.Errorf("%w: bags=%q", ErrInvalidOption, s)
+		}
+	}
+
+	if s := q.Get("limit"); s != "" {
+		if opts.Limit, err = strconv.Atoi(s); err != nil || opts.Limit < 1 {
+			return Options{}, fmt.Errorf("%w: limit=%q", ErrInvalidOption, s)
+		}
+		if opts.Limit > MaxLimit {
+			opts.Limit = MaxLimit
+		}
+	}
+	if s := q.Get("cursor"); s != "" {
+		if opts.Offset, err = decodeCursor(s); err != nil {
+			return Options{}, fmt.Errorf("%w: cursor=%q", ErrInvalidOption, s)
+		}
+	} else if s := q.Get("offset"); s != "" {
+		if opts.Offset, err = strconv.Atoi(s); err != nil || opts.Offset < 0 {
+			return Options{}, fmt.Errorf("%w: offset=%q", ErrInvalidOption, s)
+		}
+	}
+	return opts, nil
+}
+
+// Page is the result of Apply.
+type Page struct {
+	Indices    []int  // positions in the input slice, in display order
+	Total      int    // offers matching the filters, across all pages
+	Offset     int    // position of the first offer of this page
+	NextCursor string // empty on the last page
+}
+
+// Apply filters and sorts offers and returns the requested page.
+func Apply(offers []Offer, opts Options) Page {
+	var matched []int
+	for i, o := range offers {
+		if opts.match(o) {
+			matched = append(matched, i)
+		}
+	}
+	if opts.Sort != "" {
+		sort.SliceStable(matched, func(a, b int) bool {
+			oa, ob := offers[matched[a]], offers[matched[b]]
+			if oa.Incomplete != ob.Incomplete {
+				return ob.Incomplete
+			}
+			c := compare(oa, ob, opts.Sort)
+			if opts.Descending {
+				return c > 0
+			}
+			return c < 0
+		})
+	}
+
+	page := Page{Total: len(matched), Offset: opts.Offset}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if opts.Offset >= len(matched) {
+		return page
+	}
+	end := opts.Offset + limit
+	if end < len(matched) {
+		page.NextCursor = encodeCursor(end)
+	} else {
+		end = len(matched)
+	}
+	page.Indices = matched[opts.Offset:end]
+	return page
+}
+
+func (opts Options) match(o Offer) bool {
+	if len(opts.Carriers) > 0 && !anyCarrier(o.Carriers, opts.Carriers) {
+		return false
+	}
+	if opts.DepartAfter > 0 || opts.DepartBefore > 0 {
+		clock := time.Duration(o.Departure.Hour())*time.Hour + time.Duration(o.Departure.Minute())*time.Minute
+		if clock < opts.DepartAfter || (opts.DepartBefore > 0 && clock > opts.DepartBefore) {
+			return false
+		}
+	}
+	if (opts.MaxDuration > 0 || opts.MaxPrice != nil) && o.Incomplete {
+		return false
+	}
+	if opts.MaxDuration > 0 && o.Duration > opts.MaxDuration {
+		return false
+	}
+	if opts.MaxPrice != nil {
+		if c, err := o.Price.Cmp(*opts.MaxPrice); err != nil || c > 0 {
+			return false
+		}
+	}
+	if opts.BagsIncluded && !o.BagsIncluded {
+		return false
+	}
+	return true
+}
+
+// compare orders two offers by key, breaking ties by price. Sorting by price
+// leaves c at zero and goes straight to the tie break.
+func compare(a, b Offer, key string) int {
+	var c int
+	switch key {
+	case SortDuration:
+		c = cmpInt(int64(a.Duration), int64(b.Duration))
+	case SortDeparture:
+		c = cmpInt(a.Departure.Unix(), b.Departure.Unix())
+	case SortArrival:
+		c = cmpInt(a.Arrival.Unix(), b.Arrival.Unix())
+	case SortStops:
+		c = cmpInt(int64(a.Stops), int64(b.Stops))
+	}
+	if c != 0 {
+		return c
+	}
+	p, err := a.Price.Cmp(b.Price)
+	if err != nil {
+		return 0
+	}
+	return p
+}
+
+func cmpInt(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func anyCarrier(have, want []string) bool {
+	for _, h := range have {
+		for _, w := range want {
+			if h == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+var isoDuration = regexp.MustCompile(`^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// ParseDuration parses the ISO 8601 durations used by Amadeus ("PT2H35M",
+// "P1DT3H").
+func ParseDuration(s string) (time.Duration, error) {
+	m := isoDuration.FindStringSubmatch(s)
+	if m == nil || s == "P" || s == "PT" {
+		return 0, fmt.Errorf("search: invalid duration %q", s)
+	}
+	var d time.Duration
+	for i, unit := range []time.Duration{24 * time.Hour, time.Hour, time.Minute, time.Second} {
+		if m[i+1] != "" {
+			n, _ := strconv.Atoi(m[i+1])
+			d += time.Duration(n) * unit
+		}
+	}
+	return d, nil
+}
+
+// ParseTime parses the local date-times used by Amadeus ("2023-11-01T10:40:00").
+func ParseTime(s string) (time.Time, error) {
+	return time.Parse("2006-01-02T15:04:05", s)
+}
+
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("offset:" + strconv.Itoa(offset)))
+}
+
+func decodeCursor(s string) (int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return 0, err
+	}
+	n, ok := strings.CutPrefix(string(b), "offset:")
+	if !ok {
+		return 0, errors.New("search: malformed cursor")
+	}
+	offset, err := strconv.Atoi(n)
+	if err != nil || offset < 0 {
+		return 0, errors.New("search: malformed cursor")
+	}
+	return offset, nil
+}
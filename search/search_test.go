@@ -0,0 +1,143 @@
+package search
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestApplySort(t *testing.T) {
+	offers := sample(t)
+	tests := []struct {
+		sort string
+		want []int
+	}{
+		{"", []int{0, 1, 2, 3, 4}},
+		{"price", []int{1, 2, 0, 3, 4}},
+		{"-price", []int{3, 0, 1, 2, 4}},
+		{"duration", []int{3, 0, 2, 1, 4}},
+		{"departure", []int{1, 0, 2, 3, 4}},
+		{"-arrival", []int{3, 2, 1, 0, 4}},
+		// Ties are broken by price, and stable otherwise.
+		{"stops", []int{2, 0, 3, 1, 4}},
+	}
+	for _, tt := range tests {
+		opts, err := ParseOptions(url.Values{"sort": {tt.sort}}, "CLP")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := Apply(offers, opts).Indices; !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("sort=%q: %v, want %v", tt.sort, got, tt.want)
+		}
+	}
+}
+
+func TestApplyFilters(t *testing.T) {
+	offers := sample(t)
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"carrier=LA", []int{0, 2, 3, 4}},
+		{"carrier=h2,ja", []int{1, 2}},
+		{"departAfter=08:00&departBefore=14:00", []int{0, 2, 4}},
+		{"departAfter=21:00", []int{3}},
+		{"maxDuration=PT4H", []int{0, 2, 3}},
+		{"maxDuration=3h", []int{0, 3}},
+		{"maxPrice=250000", []int{0, 1, 2}},
+		{"bags=true", []int{0, 3}},
+		{"carrier=LA&bags=true&maxPrice=300000", []int{0}},
+	}
+	for _, tt := range tests {
+		q, _ := url.ParseQuery(tt.query)
+		opts, err := ParseOptions(q, "CLP")
+		if err != nil {
+			t.Fatalf("%s: %v", tt.query, err)
+		}
+		page := Apply(offers, opts)
+		if !reflect.DeepEqual(page.Indices, tt.want) {
+			t.Errorf("%s: %v, want %v", tt.query, page.Indices, tt.want)
+		}
+		if page.Total != len(tt.want) {
+			t.Errorf("%s: total %d, want %d", tt.query, page.Total, len(tt.want))
+		}
+	}
+}
+
+func TestApplyPaging(t *testing.T) {
+	offers := make([]Offer, 7)
+	for i := range offers {
+		offers[i].Price = clp(t, "1000")
+	}
+
+	page := Apply(offers, Options{Limit: 3})
+	if !reflect.DeepEqual(page.Indices, []int{0, 1, 2}) || page.Total != 7 || page.NextCursor == "" {
+		t.Fatalf("first page %+v", page)
+	}
+	opts, err := ParseOptions(url.Values{"limit": {"3"}, "cursor": {page.NextCursor}}, "CLP")
+	if err != nil {
+		t.Fatal(err)
+	}
+	page = Apply(offers, opts)
+	if !reflect.DeepEqual(page.Indices, []int{3, 4, 5}) || page.Offset != 3 {
+		t.Fatalf("second page %+v", page)
+	}
+	opts, _ = ParseOptions(url.Values{"limit": {"3"}, "cursor": {page.NextCursor}}, "CLP")
+	page = Apply(offers, opts)
+	if !reflect.DeepEqual(page.Indices, []int{6}) || page.NextCursor != "" {
+		t.Errorf("last page %+v, want [6] without a cursor", page)
+	}
+
+	if page := Apply(offers, Options{Offset: 10, Limit: 3}); len(page.Indices) != 0 || page.Total != 7 {
+		t.Errorf("past the end: %+v", page)
+	}
+	if page := Apply(offers, Options{}); len(page.Indices) != 7 || page.NextCursor != "" {
+		t.Errorf("default limit: %+v", page)
+	}
+}
+
+func TestParseOptions(t *testing.T) {
+	opts, err := ParseOptions(url.Values{}, "CLP")
+	if err != nil || opts.Limit != DefaultLimit {
+		t.Errorf("defaults: %+v, %v", opts, err)
+	}
+	opts, err = ParseOptions(url.Values{"limit": {"1000"}, "offset": {"20"}}, "CLP")
+	if err != nil || opts.Limit != MaxLimit || opts.Offset != 20 {
+		t.Errorf("limit over the maximum: %+v, %v", opts, err)
+	}
+	opts, err = ParseOptions(url.Values{"maxPrice": {"99.5"}}, "usd")
+	if err != nil || opts.MaxPrice.String() != "99.50 USD" {
+		t.Errorf("maxPrice: %+v, %v", opts.MaxPrice, err)
+	}
+
+	for _, query := range []string{
+		"sort=cheapest", "departAfter=8am", "departBefore=25:00", "maxDuration=long",
+		"maxPrice=lots", "maxPrice=.", "bags=maybe", "limit=0", "limit=x", "offset=-1",
+		"cursor=!!", "cursor=b2Zmc2V0Oi0x", // "offset:-1"
+	} {
+		q, _ := url.ParseQuery(query)
+		if _, err := ParseOptions(q, "CLP"); !errors.Is(err, ErrInvalidOption) {
+			t.Errorf("%s: err = %v, want ErrInvalidOption", query, err)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	for s, want := range map[string]time.Duration{
+		"PT2H35M": 2*time.Hour + 35*time.Minute,
+		"PT45M":   45 * time.Minute,
+		"P1DT3H":  27 * time.Hour,
+		"PT30S":   30 * time.Second,
+	} {
+		if got, err := ParseDuration(s); err != nil || got != want {
+			t.Errorf("ParseDuration(%q) = %v, %v; want %v", s, got, err, want)
+		}
+	}
+	for _, s := range []string{"", "P", "PT", "2H", "PT2X", "PT-1H"} {
+		if _, err := ParseDuration(s); err == nil {
+			t.Errorf("ParseDuration(%q) succeeded", s)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+// Package secrets reads and writes the encrypted secrets file, so credentials
+// can be kept next to the server without storing them in plain text.
+//
+// The file holds KEY="value" lines, in the same format as local.env, sealed
+// with AES-256-GCM. It is a single line of text:
+//
+//	gotravel-secrets:v1:<base64 of nonce and ciphertext>
+//
+// The key is 32 random bytes encoded in base64, as returned by GenerateKey.
+package secrets
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+const header = "gotravel-secrets:v1:"
+
+var (
+	// ErrInvalidKey is returned for keys that are not 32 bytes of base64.
+	ErrInvalidKey = errors.New("secrets: key must be 32 bytes encoded in base64")
+	// ErrDecrypt is returned when the file was sealed with another key or was
+	// modified.
+	ErrDecrypt = errors.New("secrets: wrong key or corrupted file")
+)
+
+// KeyFromEnv returns the key in the file named by SECRETS_KEY_FILE or, when
+// it is not set, in SECRETS_KEY. It returns "" when neither is set.
+func KeyFromEnv() (string, error) {
+	path := os.Getenv("SECRETS_KEY_FILE")
+	if path == "" {
+		return os.Getenv("SECRETS_KEY"), nil
+	}
+	key, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("secrets: SECRETS_KEY_FILE: %w", err)
+	}
+	return string(key), nil
+}
+
+// GenerateKey returns a new random key.
+func GenerateKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+func parseKey(key string) (cipher.AEAD, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(key))
+	if err != nil || len(raw) != 32 {
+		return nil, ErrInvalidKey
+	}
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Seal encrypts the values with key.
+func Seal(key string, values map[string]string) ([]byte, error) {
+	aead, err := parseKey(key)
+	if err != nil {
+		return nil, err
+	}
+	plaintext := marshal(values)
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(header))
+	return []byte(header + base64.StdEncoding.EncodeToString(sealed) + "\n"), nil
+}
+
+// Open decrypts data sealed by Seal.
+func Open(key string, data []byte) (map[string]string, error) {
+	aead, err := parseKey(key)
+	if err != nil {
+		return nil, err
+	}
+	encoded, ok := strings.CutPrefix(string(bytes.TrimSpace(data)), header)
+	if !ok {
+		return nil, fmt.Errorf("secrets: not a secrets file")
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return nil, ErrDecrypt
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(header))
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return unmarshal(string(plaintext))
+}
+
+// marshal writes one KEY="value" line per value, sorted by key. Every value
+// is quoted, so "0012" or "a#b" come back from unmarshal unchanged.
+func marshal(values map[string]string) string {
+	var b strings.Builder
+	for _, key := range Keys(values) {
+		b.WriteString(key)
+		b.WriteString(`="`)
+		b.WriteString(quoteEscaper.Replace(values[key]))
+		b.WriteString("\"\n")
+	}
+	return b.String()
+}
+
+var quoteEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`)
+
+// unmarshal reads the lines written by marshal. It also accepts unquoted
+// values, as written by earlier versions for numbers.
+func unmarshal(plaintext string) (map[string]string, error) {
+	values := make(map[string]string)
+	for i, line := range strings.Split(plaintext, "\n") {
+		if line == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("secrets: line %d: expected KEY=value", i+1)
+		}
+		if strings.HasPrefix(value, `"`) {
+			unquoted, err := unquote(value)
+			if err != nil {
+				return nil, fmt.Errorf("secrets: line %d: %w", i+1, err)
+			}
+			value = unquoted
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+func unquote(value string) (string, error) {
+	if len(value) < 2 || !strings.HasSuffix(value, `"`) {
+		return "", errors.New("unterminated quoted value")
+	}
+	value = value[1 : len(value)-1]
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c != '\\' {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		if i == len(value) {
+			return "", errors.New("unterminated escape")
+		}
+		switch value[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		default:
+			b.WriteByte(value[i])
+		}
+	}
+	return b.String(), nil
+}
+
+// ReadFile decrypts the secrets file at path.
+func ReadFile(path, key string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	values, err := Open(key, data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return values, nil
+}
+
+// WriteFile seals the values into path, readable only by its owner.
+func WriteFile(path, key string, values map[string]string) error {
+	data, err := Seal(key, values)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// Keys returns the sorted names of the values, to list a file's contents
+// without printing them.
+func Keys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,63 @@
+package secrets
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSealOpenRoundTrip(t *testing.T) {
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := map[string]string{
+		"LEADING_ZEROS": "0012",
+		"NUMBER":        "42",
+		"HASH":          "abc#def",
+		"EQUALS":        "a=b==",
+		"DOUBLE_QUOTES": `say "hi"`,
+		"SINGLE_QUOTES": "it's",
+		"BACKSLASH":     `C:\path\n`,
+		"NEWLINES":      "line one\nline two\r\n",
+		"DOLLAR":        "$HOME and ${PATH}",
+		"SPACES":        "  padded  ",
+		"EMPTY":         "",
+		"CONNECTION":    "mongodb://user:p@ss#word@127.0.0.1:27017/db?x=1&y=2",
+	}
+	sealed, err := Seal(key, values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opened, err := Open(key, sealed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(opened, values) {
+		t.Errorf("Open(Seal(values)) = %q, want %q", opened, values)
+	}
+}
+
+func TestOpenWrongKey(t *testing.T) {
+	key, _ := GenerateKey()
+	other, _ := GenerateKey()
+	sealed, err := Seal(key, map[string]string{"A": "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(other, sealed); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("Open with another key: err = %v, want ErrDecrypt", err)
+	}
+}
+
+func TestUnmarshalUnquoted(t *testing.T) {
+	// Files sealed by earlier versions wrote numbers without quotes.
+	values, err := unmarshal("A=12\nB=\"x\\$y\"\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"A": "12", "B": "x$y"}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("unmarshal = %q, want %q", values, want)
+	}
+}
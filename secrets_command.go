@@ -0,0 +1,102 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/joho/godotenv"
+
+	"go-quickstart/secrets"
+)
+
+const secretsUsage = `usage: gotravel secrets <command> [flags]
+
+Manages the encrypted secrets file read with -secrets-file. The key is read
+from SECRETS_KEY_FILE or SECRETS_KEY.
+
+commands:
+  keygen                          print a new key
+  seal   [-in local.env] [-out secrets.enc]
+                                  encrypt KEY=value lines
+  list   [-in secrets.enc]        print the names of the sealed values
+  unseal [-in secrets.enc]        print the sealed values in plain text
+`
+
+// secretsCommand runs "gotravel secrets" and returns the exit code.
+func secretsCommand(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, secretsUsage)
+		return 2
+	}
+	if err := runSecretsCommand(args[0], args[1:], stdout); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		fmt.Fprintln(stderr, "gotravel secrets:", err)
+		return 1
+	}
+	return 0
+}
+
+func runSecretsCommand(command string, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("gotravel secrets "+command, flag.ContinueOnError)
+	in := fs.String("in", "secrets.enc", "input file")
+	out := fs.String("out", "secrets.enc", "output file")
+	if command == "seal" {
+		*in = "local.env"
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if command == "keygen" {
+		key, err := secrets.GenerateKey()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, key)
+		return nil
+	}
+
+	key, err := secrets.KeyFromEnv()
+	if err != nil {
+		return err
+	}
+	if key == "" {
+		return errors.New("SECRETS_KEY or SECRETS_KEY_FILE is required")
+	}
+	switch command {
+	case "seal":
+		values, err := godotenv.Read(*in)
+		if err != nil {
+			return err
+		}
+		if err := secrets.WriteFile(*out, key, values); err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "sealed %d values into %s; remove them from %s\n", len(values), *out, *in)
+	case "list":
+		values, err := secrets.ReadFile(*in, key)
+		if err != nil {
+			return err
+		}
+		for _, name := range secrets.Keys(values) {
+			fmt.Fprintln(stdout, name)
+		}
+	case "unseal":
+		values, err := secrets.ReadFile(*in, key)
+		if err != nil {
+			return err
+		}
+		text, err := godotenv.Marshal(values)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, text)
+	default:
+		return fmt.Errorf("unknown command %q\n\n%s", command, secretsUsage)
+	}
+	return nil
+}
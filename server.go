@@ -3,70 +3,510 @@ package main
 import (
 	"bytes"
 	"context"
+	"crypto/subtle"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"google.golang.org/grpc"
+	"gopkg.in/yaml.v3"
+
+	"go-quickstart/cassette"
+	"go-quickstart/config"
+	"go-quickstart/currency"
+	"go-quickstart/farerules"
+	"go-quickstart/health"
+	"go-quickstart/history"
+	"go-quickstart/ical"
+	"go-quickstart/ids"
+	"go-quickstart/logging"
+	"go-quickstart/mailer"
+	"go-quickstart/metrics"
+	"go-quickstart/money"
+	"go-quickstart/openapi"
+	"go-quickstart/search"
+	"go-quickstart/tracing"
+	"go-quickstart/upstream"
+	"go-quickstart/watch"
+	"go-quickstart/webhook"
 )
 
-func connectToMongoDB() (*mongo.Client, error) {
-	err := godotenv.Load("local.env")
+// cfg is the configuration loaded by main. It holds the defaults until then.
+var cfg = config.Default()
+
+// rates holds the exchange-rate table used to show prices in other currencies.
+var rates currency.Service
+
+// watchStore keeps price watches.
+type watchStore interface {
+	watch.Store
+	Insert(ctx context.Context, w watch.Watch) error
+	// Owned returns the watches created with the owner's API key.
+	Owned(ctx context.Context, owner string) ([]watch.Watch, error)
+	// Delete removes the owner's watch and reports whether it existed.
+	Delete(ctx context.Context, id, owner string) (bool, error)
+}
+
+// watches stores the price watches checked by the background scheduler.
+var watches watchStore = mongoWatchStore{}
+
+// bookingHistory records the timeline of every booking.
+var bookingHistory = history.Recorder{Store: mongoHistoryStore{}}
+
+// background tracks work that outlives its request, such as confirmation
+// emails, so shutdown can wait for it.
+var background sync.WaitGroup
+
+// amadeusClient is shared by every call to Amadeus so connections are reused.
+// main replaces its transport to record or replay the calls.
+var amadeusClient = &http.Client{
+	Timeout:   cfg.Amadeus.Timeout,
+	Transport: amadeusTransport(nil),
+}
+
+// amadeusTransport traces every attempt and forwards the request ID over base
+// so calls can be followed in Amadeus.
+func amadeusTransport(base http.RoundTripper) http.RoundTripper {
+	return otelhttp.NewTransport(logging.Transport{Base: base},
+		otelhttp.WithSpanNameFormatter(func(_ string, req *http.Request) string {
+			return "amadeus " + upstream.Operation(req)
+		}))
+}
+
+// amadeus retries Amadeus calls that fail with 429 or transient 5xx and stops
+// calling it for a while after repeated failures. main applies the configured
+// limits.
+var amadeus = &upstream.Client{
+	HTTP:        amadeusClient,
+	MaxAttempts: cfg.Amadeus.MaxAttempts,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+	Breaker:     upstream.NewBreaker(5, cfg.Amadeus.BreakerCooldown),
+	Observe:     observeProvider(config.ProviderAmadeus),
+}
+
+// observeProvider records the metrics of every call to a flight provider.
+func observeProvider(provider string) func(req *http.Request, status int, err error, elapsed time.Duration) {
+	return func(req *http.Request, status int, err error, elapsed time.Duration) {
+		if errors.Is(err, upstream.ErrCircuitOpen) {
+			metrics.CircuitOpen(provider, upstream.Operation(req))
+			return
+		}
+		metrics.Upstream(provider, upstream.Operation(req), status, err, elapsed)
+	}
+}
+
+// webhooks delivers booking lifecycle events to the subscribed URLs.
+var webhooks = &webhook.Dispatcher{
+	Store:       mongoWebhookStore{},
+	Client:      &http.Client{Timeout: cfg.Webhooks.Timeout},
+	MaxAttempts: 6,
+	BaseDelay:   2 * time.Second,
+	MaxDelay:    5 * time.Minute,
+}
+
+// connectToMongoDB connects and pings the deployment within ctx. Every
+// operation on the returned client is bounded by the configured timeout.
+func connectToMongoDB(ctx context.Context) (*mongo.Client, error) {
+	URI, err := cfg.Mongo.ConnectionString()
 	if err != nil {
-		log.Fatalf("Some error occured. Err: %s", err)
+		return nil, err
 	}
-	URI := os.Getenv("CONNECTION_STRING")
 	// Use the SetServerAPIOptions() method to set the Stable API version to 1
 	serverAPI := options.ServerAPI(options.ServerAPIVersion1)
-	opts := options.Client().ApplyURI(URI).SetServerAPIOptions(serverAPI)
+	opts := options.Client().ApplyURI(URI).SetServerAPIOptions(serverAPI).SetTimeout(cfg.Mongo.Timeout).SetMonitor(tracing.MongoMonitor(metrics.MongoMonitor()))
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.Mongo.Timeout)
+	defer cancel()
 
 	// Create a new client and connect to the server
-	client, err := mongo.Connect(context.TODO(), opts)
+	client, err := mongo.Connect(ctx, opts)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	// Send a ping to confirm a successful connection
-	if err := client.Database("admin").RunCommand(context.TODO(), bson.D{{"ping", 1}}).Err(); err != nil {
-		panic(err)
+	if err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "ping", Value: 1}}).Err(); err != nil {
+		client.Disconnect(context.Background())
+		return nil, err
 	}
-	fmt.Println("Pinged your deployment. You successfully connected to MongoDB!")
+	slog.DebugContext(ctx, "connected to MongoDB")
 	return client, nil
 }
 
 func closeMongoDBConnection(client *mongo.Client) {
-	if err := client.Disconnect(context.Background()); err != nil {
-		fmt.Println("Error al desconectar de MongoDB:", err)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Mongo.Timeout)
+	defer cancel()
+	if err := client.Disconnect(ctx); err != nil {
+		slog.Warn("disconnecting from MongoDB failed", "error", err)
 	}
 }
 
-func insertData(client *mongo.Client, booking BookingResponse) error {
-	collection := client.Database("gotravel").Collection("reservations")
+// bookingStore keeps the orders created through the API.
+type bookingStore interface {
+	Insert(ctx context.Context, booking BookingResponse) error
+}
+
+// bookings stores the orders created by bookingHandler.
+var bookings bookingStore = mongoBookingStore{}
+
+// mongoBookingStore keeps bookings in the configured bookings collection.
+type mongoBookingStore struct{}
 
-	_, err := collection.InsertOne(context.Background(), booking)
+func (mongoBookingStore) Insert(ctx context.Context, booking BookingResponse) error {
+	client, err := connectToMongoDB(ctx)
 	if err != nil {
 		return err
 	}
+	defer closeMongoDBConnection(client)
 
-	fmt.Println("Documento insertado con éxito")
+	collection := client.Database(cfg.Mongo.Database).Collection(cfg.Mongo.BookingsCollection)
+	if _, err := collection.InsertOne(ctx, booking); err != nil {
+		return err
+	}
+	slog.DebugContext(ctx, "booking saved", "orderId", booking.Data.ID)
 	return nil
 }
 
+// rateStore keeps the exchange-rate tables.
+type rateStore interface {
+	Save(ctx context.Context, table currency.Table) error
+	// Latest returns currency.ErrNoRates when no table has been saved.
+	Latest(ctx context.Context) (currency.Table, error)
+}
+
+// rateTables stores the tables sent to updateRatesHandler or read from
+// cfg.RatesFile.
+var rateTables rateStore = mongoRateStore{}
+
+// mongoRateStore keeps exchange-rate tables in the exchange_rates
+// collection. Every table is a new document, so older tables are kept for
+// reference.
+type mongoRateStore struct{}
+
+func (mongoRateStore) collection(client *mongo.Client) *mongo.Collection {
+	return client.Database(cfg.Mongo.Database).Collection("exchange_rates")
+}
+
+func (s mongoRateStore) Save(ctx context.Context, table currency.Table) error {
+	client, err := connectToMongoDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeMongoDBConnection(client)
+
+	_, err = s.collection(client).InsertOne(ctx, table)
+	return err
+}
+
+func (s mongoRateStore) Latest(ctx context.Context) (currency.Table, error) {
+	client, err := connectToMongoDB(ctx)
+	if err != nil {
+		return currency.Table{}, err
+	}
+	defer closeMongoDBConnection(client)
+
+	var table currency.Table
+	opts := options.FindOne().SetSort(bson.D{{Key: "updatedAt", Value: -1}})
+	err = s.collection(client).FindOne(ctx, bson.D{}, opts).Decode(&table)
+	if err == mongo.ErrNoDocuments {
+		return currency.Table{}, currency.ErrNoRates
+	}
+	return table, err
+}
+
+// mongoWatchStore keeps price watches in the price_watches collection.
+type mongoWatchStore struct{}
+
+func (mongoWatchStore) collection(client *mongo.Client) *mongo.Collection {
+	return client.Database(cfg.Mongo.Database).Collection("price_watches")
+}
+
+func (s mongoWatchStore) Insert(ctx context.Context, w watch.Watch) error {
+	client, err := connectToMongoDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeMongoDBConnection(client)
+
+	_, err = s.collection(client).InsertOne(ctx, w)
+	return err
+}
+
+func (s mongoWatchStore) List(ctx context.Context) ([]watch.Watch, error) {
+	return s.find(ctx, bson.D{})
+}
+
+func (s mongoWatchStore) Owned(ctx context.Context, owner string) ([]watch.Watch, error) {
+	return s.find(ctx, bson.D{{Key: "owner", Value: owner}})
+}
+
+func (s mongoWatchStore) find(ctx context.Context, filter bson.D) ([]watch.Watch, error) {
+	client, err := connectToMongoDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer closeMongoDBConnection(client)
+
+	cursor, err := s.collection(client).Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	watches := []watch.Watch{}
+	err = cursor.All(ctx, &watches)
+	return watches, err
+}
+
+func (s mongoWatchStore) Update(ctx context.Context, w watch.Watch) error {
+	client, err := connectToMongoDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeMongoDBConnection(client)
+
+	_, err = s.collection(client).ReplaceOne(ctx, bson.D{{Key: "_id", Value: w.ID}}, w)
+	return err
+}
+
+func (s mongoWatchStore) Delete(ctx context.Context, id, owner string) (bool, error) {
+	client, err := connectToMongoDB(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer closeMongoDBConnection(client)
+
+	result, err := s.collection(client).DeleteOne(ctx, bson.D{{Key: "_id", Value: id}, {Key: "owner", Value: owner}})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// mongoWebhookStore keeps webhook subscriptions and the delivery log in the
+// webhook_subscriptions and webhook_deliveries collections.
+type mongoWebhookStore struct{}
+
+func (mongoWebhookStore) subscriptions(client *mongo.Client) *mongo.Collection {
+	return client.Database(cfg.Mongo.Database).Collection("webhook_subscriptions")
+}
+
+func (mongoWebhookStore) deliveries(client *mongo.Client) *mongo.Collection {
+	return client.Database(cfg.Mongo.Database).Collection("webhook_deliveries")
+}
+
+func (s mongoWebhookStore) InsertSubscription(ctx context.Context, sub webhook.Subscription) error {
+	client, err := connectToMongoDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeMongoDBConnection(client)
+
+	_, err = s.subscriptions(client).InsertOne(ctx, sub)
+	return err
+}
+
+func (s mongoWebhookStore) DeleteSubscription(ctx context.Context, id string) (bool, error) {
+	client, err := connectToMongoDB(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer closeMongoDBConnection(client)
+
+	result, err := s.subscriptions(client).DeleteOne(ctx, bson.D{{Key: "_id", Value: id}})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+func (s mongoWebhookStore) Subscriptions(ctx context.Context) ([]webhook.Subscription, error) {
+	client, err := connectToMongoDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer closeMongoDBConnection(client)
+
+	cursor, err := s.subscriptions(client).Find(ctx, bson.D{})
+	if err != nil {
+		return nil, err
+	}
+	subscriptions := []webhook.Subscription{}
+	err = cursor.All(ctx, &subscriptions)
+	return subscriptions, err
+}
+
+func (s mongoWebhookStore) Subscription(ctx context.Context, id string) (webhook.Subscription, error) {
+	client, err := connectToMongoDB(ctx)
+	if err != nil {
+		return webhook.Subscription{}, err
+	}
+	defer closeMongoDBConnection(client)
+
+	var sub webhook.Subscription
+	err = s.subscriptions(client).FindOne(ctx, bson.D{{Key: "_id", Value: id}}).Decode(&sub)
+	if err == mongo.ErrNoDocuments {
+		return sub, webhook.ErrNotFound
+	}
+	return sub, err
+}
+
+func (s mongoWebhookStore) SaveDelivery(ctx context.Context, delivery webhook.Delivery) error {
+	client, err := connectToMongoDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeMongoDBConnection(client)
+
+	_, err = s.deliveries(client).ReplaceOne(ctx, bson.D{{Key: "_id", Value: delivery.ID}}, delivery, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (s mongoWebhookStore) Delivery(ctx context.Context, id string) (webhook.Delivery, error) {
+	client, err := connectToMongoDB(ctx)
+	if err != nil {
+		return webhook.Delivery{}, err
+	}
+	defer closeMongoDBConnection(client)
+
+	var delivery webhook.Delivery
+	err = s.deliveries(client).FindOne(ctx, bson.D{{Key: "_id", Value: id}}).Decode(&delivery)
+	if err == mongo.ErrNoDocuments {
+		return delivery, webhook.ErrNotFound
+	}
+	return delivery, err
+}
+
+func (s mongoWebhookStore) PendingDeliveries(ctx context.Context) ([]webhook.Delivery, error) {
+	client, err := connectToMongoDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer closeMongoDBConnection(client)
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	cursor, err := s.deliveries(client).Find(ctx, bson.D{{Key: "status", Value: webhook.StatusPending}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	deliveries := []webhook.Delivery{}
+	err = cursor.All(ctx, &deliveries)
+	return deliveries, err
+}
+
+func (s mongoWebhookStore) Deliveries(ctx context.Context, limit int64) ([]webhook.Delivery, error) {
+	client, err := connectToMongoDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer closeMongoDBConnection(client)
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}).SetLimit(limit)
+	cursor, err := s.deliveries(client).Find(ctx, bson.D{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	deliveries := []webhook.Delivery{}
+	err = cursor.All(ctx, &deliveries)
+	return deliveries, err
+}
+
+// mongoHistoryStore appends booking events to the booking_events collection.
+// It has no update or delete operations on purpose.
+type mongoHistoryStore struct{}
+
+func (mongoHistoryStore) collection(client *mongo.Client) *mongo.Collection {
+	return client.Database(cfg.Mongo.Database).Collection("booking_events")
+}
+
+func (s mongoHistoryStore) Append(ctx context.Context, event history.Event) error {
+	client, err := connectToMongoDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeMongoDBConnection(client)
+
+	_, err = s.collection(client).InsertOne(ctx, event)
+	return err
+}
+
+func (s mongoHistoryStore) Timeline(ctx context.Context, orderID string) ([]history.Event, error) {
+	client, err := connectToMongoDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer closeMongoDBConnection(client)
+
+	opts := options.Find().SetSort(bson.D{{Key: "at", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := s.collection(client).Find(ctx, bson.D{{Key: "orderId", Value: orderID}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	events := []history.Event{}
+	err = cursor.All(ctx, &events)
+	return events, err
+}
+
 type searchParams struct {
-	Origen      string `json:"origen"`
-	Destino     string `json:"destino"`
-	FechaSalida string `json:"fecha"`
-	Adultos     string `json:"adultos"`
+	Origen      string `json:"origen" form:"origen"`
+	Destino     string `json:"destino" form:"destino"`
+	FechaSalida string `json:"fecha" form:"fecha"`
+	Adultos     string `json:"adultos" form:"adultos"`
+	Moneda      string `json:"moneda" form:"moneda"` // optional display currency
 }
 
 type OrderSearch struct {
-	OrderID string `json:"orderID"`
+	OrderID string `json:"orderID" form:"orderID"`
+	Moneda  string `json:"moneda" form:"moneda"` // optional display currency
+}
+
+// ErrorResponse is the body of every error response.
+type ErrorResponse struct {
+	Error   string          `json:"error"`
+	Details json.RawMessage `json:"details,omitempty"`
+}
+
+// bindBodyOrQuery reads the input of a GET endpoint from the query string,
+// or from a JSON body as older clients send it.
+func bindBodyOrQuery(c *gin.Context, obj any) error {
+	if c.Request.ContentLength != 0 && c.Request.Body != nil && c.Request.Body != http.NoBody {
+		return c.ShouldBindJSON(obj)
+	}
+	return c.ShouldBindQuery(obj)
+}
+
+// DisplayPrice is a price converted to the currency requested by the user.
+// It is referential only: the offer is still billed in BillingCurrency for
+// the amounts in BilledTotal and BilledGrandTotal.
+type DisplayPrice struct {
+	Currency         string    `json:"currency"`
+	Total            string    `json:"total"`
+	GrandTotal       string    `json:"grandTotal,omitempty"`
+	BillingCurrency  string    `json:"billingCurrency"`
+	BilledTotal      string    `json:"billedTotal"`
+	BilledGrandTotal string    `json:"billedGrandTotal,omitempty"`
+	ExchangeRate     string    `json:"exchangeRate"`
+	RatesUpdatedAt   time.Time `json:"ratesUpdatedAt"`
+	Converted        bool      `json:"converted"`
 }
 
 type TokenResponse struct {
@@ -75,11 +515,21 @@ type TokenResponse struct {
 	ExpiresIn   int    `json:"expires_in"`
 }
 
+// SearchMeta describes the page of results returned by searchHandler.
+type SearchMeta struct {
+	Count      int    `json:"count"`
+	Total      int    `json:"total"`
+	Offset     int    `json:"offset"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
 type FlighOffers struct {
+	Meta SearchMeta `json:"meta"`
 	Data []struct {
 		Type                     string `json:"type"`
 		ID                       string `json:"id"`
 		Source                   string `json:"source"`
+		Provider                 string `json:"provider,omitempty"` // set by goTravel
 		InstantTicketingRequired bool   `json:"instantTicketingRequired"`
 		NonHomogeneous           bool   `json:"nonHomogeneous"`
 		OneWay                   bool   `json:"oneWay"`
@@ -121,6 +571,9 @@ type FlighOffers struct {
 			} `json:"fees"`
 			GrandTotal string `json:"grandTotal"`
 		} `json:"price"`
+		DisplayPrice   *DisplayPrice  `json:"displayPrice,omitempty"`
+		Scores         *search.Scores `json:"scores,omitempty"`
+		Tags           []string       `json:"tags,omitempty"`
 		PricingOptions struct {
 			FareType                []string `json:"fareType"`
 			IncludedCheckedBagsOnly bool     `json:"includedCheckedBagsOnly"`
@@ -141,6 +594,7 @@ type FlighOffers struct {
 				FareBasis           string `json:"fareBasis"`
 				Class               string `json:"class"`
 				IncludedCheckedBags struct {
+					Quantity   int    `json:"quantity"`
 					Weight     int    `json:"weight"`
 					WeightUnit string `json:"weightUnit"`
 				} `json:"includedCheckedBags"`
@@ -177,6 +631,7 @@ type FlightPriceRequest struct {
 			Type                     string `json:"type"`
 			ID                       string `json:"id"`
 			Source                   string `json:"source"`
+			Provider                 string `json:"provider,omitempty"` // set by goTravel
 			InstantTicketingRequired bool   `json:"instantTicketingRequired"`
 			NonHomogeneous           bool   `json:"nonHomogeneous"`
 			OneWay                   bool   `json:"oneWay"`
@@ -255,6 +710,7 @@ type PricingResponse struct {
 			Type                     string `json:"type"`
 			ID                       string `json:"id"`
 			Source                   string `json:"source"`
+			Provider                 string `json:"provider,omitempty"` // set by goTravel
 			InstantTicketingRequired bool   `json:"instantTicketingRequired"`
 			NonHomogeneous           bool   `json:"nonHomogeneous"`
 			LastTicketingDate        string `json:"lastTicketingDate"`
@@ -292,6 +748,7 @@ type PricingResponse struct {
 				GrandTotal      string `json:"grandTotal"`
 				BillingCurrency string `json:"billingCurrency"`
 			} `json:"price"`
+			DisplayPrice   *DisplayPrice `json:"displayPrice,omitempty"`
 			PricingOptions struct {
 				FareType                []string `json:"fareType"`
 				IncludedCheckedBagsOnly bool     `json:"includedCheckedBagsOnly"`
@@ -322,6 +779,17 @@ type PricingResponse struct {
 			} `json:"travelerPricings"`
 		} `json:"flightOffers"`
 	} `json:"data"`
+	Included struct {
+		DetailedFareRules map[string]struct {
+			FareBasis string `json:"fareBasis"`
+			Name      string `json:"name"`
+			FareNotes struct {
+				Descriptions []farerules.Note `json:"descriptions"`
+			} `json:"fareNotes"`
+			SegmentID string `json:"segmentId"`
+		} `json:"detailed-fare-rules,omitempty"`
+	} `json:"included"`
+	FareRules []farerules.Rules `json:"fareRules,omitempty"`
 }
 
 type BookingRequest struct {
@@ -330,6 +798,7 @@ type BookingRequest struct {
 			Type                     string `json:"type"`
 			ID                       string `json:"id"`
 			Source                   string `json:"source"`
+			Provider                 string `json:"provider,omitempty"` // set by goTravel
 			InstantTicketingRequired bool   `json:"instantTicketingRequired"`
 			NonHomogeneous           bool   `json:"nonHomogeneous"`
 			LastTicketingDate        string `json:"lastTicketingDate"`
@@ -504,203 +973,1614 @@ type OrderResponse struct {
 				} `json:"segments"`
 			} `json:"itineraries"`
 			Price struct {
-				Total string `json:"total"`
+				Currency   string `json:"currency"`
+				Total      string `json:"total"`
+				GrandTotal string `json:"grandTotal"`
 			} `json:"price"`
+			DisplayPrice *DisplayPrice `json:"displayPrice,omitempty"`
 		} `json:"flightOffers"`
 	} `json:"data"`
 }
 
-func getToken() string {
-
-	err := godotenv.Load("local.env")
+// getToken returns an Amadeus access token, or "null" if it cannot get one
+// so the following call fails with an authorization error.
+func getToken(ctx context.Context) string {
+	accessToken, err := requestToken(ctx)
 	if err != nil {
-		log.Fatalf("Some error occured. Err: %s", err)
+		slog.ErrorContext(ctx, "Amadeus token request failed", "error", err)
+		return "null"
 	}
-	clientID := os.Getenv("CLIENT_ID")
-	clientSecret := os.Getenv("SECRET_ID")
-	tokenURL := "https://test.api.amadeus.com/v1/security/oauth2/token"
+	return accessToken
+}
+
+// requestToken asks Amadeus for an access token.
+func requestToken(ctx context.Context) (string, error) {
+	clientID := cfg.Amadeus.ClientID.Reveal()
+	clientSecret := cfg.Amadeus.ClientSecret.Reveal()
+	tokenURL := cfg.Amadeus.URL("/v1/security/oauth2/token")
 	tokenRequestData := bytes.NewBufferString(fmt.Sprintf("grant_type=client_credentials&client_id=%s&client_secret=%s", clientID, clientSecret))
 
-	resp, err := http.Post(tokenURL, "application/x-www-form-urlencoded", tokenRequestData)
+	ctx, span := tracing.Start(ctx, "amadeus.token")
+	defer span.End()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", tokenURL, tokenRequestData)
 	if err != nil {
-		fmt.Println("Error making request:", err)
-		return "null"
+		tracing.Fail(span, err)
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req = upstream.WithOperation(upstream.Idempotent(req), "token")
+	resp, err := amadeus.Do(req)
+	if err != nil {
+		metrics.TokenRefresh("amadeus", err)
+		tracing.Fail(span, err)
+		return "", err
 	}
 	defer resp.Body.Close()
 
 	var tokenResponse TokenResponse
 	err = json.NewDecoder(resp.Body).Decode(&tokenResponse)
+	if err == nil && tokenResponse.AccessToken == "" {
+		err = fmt.Errorf("token request failed: %s", resp.Status)
+	}
+	metrics.TokenRefresh("amadeus", err)
 	if err != nil {
-		fmt.Println("Error decoding response:", err)
-		return "null"
+		tracing.Fail(span, err)
+		return "", err
 	}
-	accessToken := tokenResponse.AccessToken
-	return accessToken
+	return tokenResponse.AccessToken, nil
 }
 
-func searchHandler(c *gin.Context) { // function that handles the request
-	var search searchParams
-	if err := c.BindJSON(&search); err != nil {
-		return
+// ensureRates reloads the latest exchange-rate table from MongoDB when the
+// one in use was loaded more than cfg.RatesTTL ago, so tables stored by
+// another instance are picked up. When the reload fails, the table in use is
+// kept for another TTL.
+func ensureRates(ctx context.Context) error {
+	if rates.Fresh(cfg.RatesTTL) {
+		return nil
 	}
-	var accessToken = getToken()
-	url := fmt.Sprintf("https://test.api.amadeus.com/v2/shopping/flight-offers?originLocationCode=%s&destinationLocationCode=%s&departureDate=%s&adults=%v&includedAirlineCodes=LA,JA,H2&nonStop=true&currencyCode=CLP&travelClass=ECONOMY", search.Origen, search.Destino, search.FechaSalida, search.Adultos)
-	req, err := http.NewRequest("GET", url, nil)
+	table, err := rateTables.Latest(ctx)
 	if err != nil {
-		panic(err)
+		current, ok := rates.Table()
+		if !ok {
+			return err
+		}
+		slog.WarnContext(ctx, "reloading exchange rates failed", "error", err, "updatedAt", current.UpdatedAt)
+		rates.Set(current)
+		return nil
 	}
-	req.Header.Set("Authorization", "Bearer "+accessToken)
+	rates.Set(table)
+	return nil
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		panic(err)
+// storeRates saves a new exchange-rate table and starts using it.
+func storeRates(ctx context.Context, table currency.Table) error {
+	if err := rateTables.Save(ctx, table); err != nil {
+		return err
 	}
-	defer resp.Body.Close()
+	rates.Set(table)
+	return nil
+}
 
-	var flightSearchResponse FlighOffers
-	err = json.NewDecoder(resp.Body).Decode(&flightSearchResponse)
+// toDisplayPrice converts a billed price into the display currency. It
+// returns nil when no display currency, or the billing one, is requested.
+func toDisplayPrice(ctx context.Context, display, billing, total, grandTotal string) (*DisplayPrice, error) {
+	display = strings.ToUpper(strings.TrimSpace(display))
+	if display == "" || display == billing {
+		return nil, nil
+	}
+	if err := ensureRates(ctx); err != nil {
+		return nil, err
+	}
+	billedTotal, err := money.Parse(total, billing)
 	if err != nil {
-		fmt.Println("Error decoding flight search response:", err)
-		return
+		return nil, err
+	}
+	converted, err := rates.Convert(billedTotal, display)
+	if err != nil {
+		return nil, err
+	}
+	displayPrice := &DisplayPrice{
+		Currency:        display,
+		Total:           converted.Amount.Amount(),
+		BillingCurrency: billing,
+		BilledTotal:     total,
+		ExchangeRate:    strings.TrimRight(strings.TrimRight(converted.Rate.FloatString(10), "0"), "."),
+		RatesUpdatedAt:  converted.RatesUpdatedAt,
+		Converted:       true,
+	}
+	if grandTotal != "" {
+		billedGrandTotal, err := money.Parse(grandTotal, billing)
+		if err != nil {
+			return nil, err
+		}
+		convertedGrandTotal, err := rates.Convert(billedGrandTotal, display)
+		if err != nil {
+			return nil, err
+		}
+		displayPrice.GrandTotal = convertedGrandTotal.Amount.Amount()
+		displayPrice.BilledGrandTotal = grandTotal
 	}
+	return displayPrice, nil
+}
+
+// summarizeOffers builds the summaries used to sort, filter and paginate the
+// results of a flight search.
+func summarizeOffers(offers FlighOffers) []search.Offer {
+	summaries := make([]search.Offer, len(offers.Data))
+	for i, offer := range offers.Data {
+		summary := &summaries[i]
+		price, err := money.Parse(offer.Price.GrandTotal, offer.Price.Currency)
+		if err != nil {
+			price, err = money.Parse(offer.Price.Total, offer.Price.Currency)
+		}
+		// Offers of providers billing in another currency are compared in
+		// the billing one, when the exchange rates are loaded.
+		if err == nil && price.Currency() != cfg.Search.Currency {
+			var converted currency.Conversion
+			if converted, err = rates.Convert(price, cfg.Search.Currency); err == nil {
+				price = converted.Amount
+			}
+		}
+		summary.Price = price
+		summary.Incomplete = err != nil
+
+		summary.BagsIncluded = len(offer.TravelerPricings) > 0
+		for _, travelerPricing := range offer.TravelerPricings {
+			for _, fareDetails := range travelerPricing.FareDetailsBySegment {
+				if fareDetails.IncludedCheckedBags.Quantity == 0 && fareDetails.IncludedCheckedBags.Weight == 0 {
+					summary.BagsIncluded = false
+				}
+			}
+		}
 
-	for i := range flightSearchResponse.Data {
-		for j := range flightSearchResponse.Data[i].Itineraries {
-			for k := range flightSearchResponse.Data[i].Itineraries[j].Segments {
-				if flightSearchResponse.Data[i].Itineraries[j].Segments[k].Operating.CarrierCode == "" {
-					// Si 'operating.carrierCode' está vacío, asigna el valor de 'carrierCode' de nivel superior.
-					flightSearchResponse.Data[i].Itineraries[j].Segments[k].Operating.CarrierCode = flightSearchResponse.Data[i].Itineraries[j].Segments[k].CarrierCode
+		for j, itinerary := range offer.Itineraries {
+			duration, err := search.ParseDuration(itinerary.Duration)
+			if err != nil {
+				summary.Incomplete = true
+			}
+			summary.Duration += duration
+			if len(itinerary.Segments) == 0 {
+				summary.Incomplete = true
+				continue
+			}
+			summary.Stops += len(itinerary.Segments) - 1
+			for k, segment := range itinerary.Segments {
+				summary.Stops += segment.NumberOfStops
+				summary.Carriers = append(summary.Carriers, segment.CarrierCode, segment.Operating.CarrierCode)
+				if j == 0 && k == 0 {
+					summary.Departure, _ = search.ParseTime(segment.Departure.At)
 				}
+				summary.Arrival, _ = search.ParseTime(segment.Arrival.At)
 			}
 		}
 	}
-	c.IndentedJSON(http.StatusCreated, flightSearchResponse)
+	return summaries
 }
 
-func priceHandler(c *gin.Context) { // function that handles the request
-
-	var searchPrice FlightPriceRequest
-	if err := c.BindJSON(&searchPrice); err != nil {
-		return
-	}
+// searchOptionsFromQuery reads the sort, filter and pagination options of a
+// search request. Prices in filters are in the billing currency.
+func searchOptionsFromQuery(c *gin.Context) (search.Options, error) {
+	return search.ParseOptions(c.Request.URL.Query(), cfg.Search.Currency)
+}
 
-	var accessToken = getToken()
-	pricingData, _ := json.Marshal(searchPrice)
-	req, err := http.NewRequest("POST", "https://test.api.amadeus.com/v1/shopping/flight-offers/pricing", bytes.NewBuffer(pricingData))
-	if err != nil {
-		panic(err)
+// scoreWeights returns the weights of the "best" tag, taken from s, the
+// weights query parameter, or else the configured weights or the defaults.
+func scoreWeights(s string) (search.Weights, error) {
+	if s != "" {
+		return search.ParseWeights(s)
 	}
-	req.Header.Set("Authorization", "Bearer "+accessToken)
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Fatal(err)
+	if s := cfg.Search.BestWeights; s != "" {
+		return search.ParseWeights(s)
 	}
-	defer resp.Body.Close()
+	return search.DefaultWeights, nil
+}
 
-	var pricingResponse PricingResponse
-	err = json.NewDecoder(resp.Body).Decode(&pricingResponse)
-	if err != nil {
-		fmt.Println("Error decoding flight search response:", err)
-		return
+// scoreOffers attaches scores and the cheapest, fastest and best tags to
+// every offer. It runs before pagination so tags are relative to all results.
+func scoreOffers(offers FlighOffers, summaries []search.Offer, weights search.Weights) {
+	scores, tags := search.Score(summaries, weights)
+	for i := range offers.Data {
+		offers.Data[i].Scores = &scores[i]
+		offers.Data[i].Tags = tags[i]
 	}
-
-	c.IndentedJSON(http.StatusCreated, pricingResponse)
 }
 
-func bookingHandler(c *gin.Context) {
-
-	var bookingRequest BookingRequest
-	var accessToken = getToken()
+// paginateOffers filters and sorts the offers and keeps only the requested
+// page, filling in the response meta.
+func paginateOffers(offers FlighOffers, summaries []search.Offer, opts search.Options) FlighOffers {
+	page := search.Apply(summaries, opts)
 
-	if err := c.BindJSON(&bookingRequest); err != nil {
-		return
+	paged := offers
+	paged.Data = offers.Data[:0:0]
+	for _, i := range page.Indices {
+		paged.Data = append(paged.Data, offers.Data[i])
 	}
-
-	bookingData, _ := json.Marshal(bookingRequest)
-	req, err := http.NewRequest("POST", "https://test.api.amadeus.com/v1/booking/flight-orders", bytes.NewBuffer(bookingData))
-	if err != nil {
-		log.Fatal(err)
+	paged.Meta = SearchMeta{
+		Count:      len(paged.Data),
+		Total:      page.Total,
+		Offset:     page.Offset,
+		NextCursor: page.NextCursor,
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+accessToken)
+	return paged
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Fatal(err)
+func searchHandler(c *gin.Context) { // function that handles the request
+	var search searchParams
+	if err := bindBodyOrQuery(c, &search); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
-	defer resp.Body.Close()
-
-	var bookingResponse BookingResponse
-	err = json.NewDecoder(resp.Body).Decode(&bookingResponse)
+	searchOptions, err := searchOptionsFromQuery(c)
 	if err != nil {
-		fmt.Println("Error decoding flight search response:", err)
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	mongo_client, err := connectToMongoDB()
+	weights, err := scoreWeights(c.Query("weights"))
 	if err != nil {
-		fmt.Println("Error al conectar a MongoDB:", err)
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	defer closeMongoDBConnection(mongo_client)
-
-	if err := insertData(mongo_client, bookingResponse); err != nil {
-		fmt.Println("Error al insertar datos en MongoDB:", err)
+	flightSearchResponse, err := searchOffers(c.Request.Context(), search, searchOptions, weights)
+	if err != nil {
+		serviceFailed(c, err)
+		return
 	}
-
-	c.IndentedJSON(http.StatusCreated, bookingResponse)
+	c.IndentedJSON(http.StatusCreated, flightSearchResponse)
 }
 
-func orderHandler(c *gin.Context) { // function that handles the request
-	var accessToken = getToken()
-	fmt.Println("HOLA", c)
-	var orderID OrderSearch
-	if err := c.BindJSON(&orderID); err != nil {
-		return
-	}
-	//fmt.Println("hola", orderID)
+// maxStreamDates caps the departure dates of a streaming search.
+const maxStreamDates = 14
 
-	client := &http.Client{}
-	url := fmt.Sprintf("https://test.api.amadeus.com/v1/booking/flight-orders/%s", orderID.OrderID)
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		log.Fatal(err)
+// searchStreamHandler searches one or more departure dates, given as
+// fecha=2026-12-01,2026-12-02, and streams the results as Server-Sent
+// Events while each search completes:
+//
+//	progress  {"batch", "completed", "total"}, first with completed 0
+//	offers    {"batch", "offers"}, filtered and sorted as in searchHandler
+//	error     {"batch", "status", "error", "details"}; the others go on
+//	done      {"batches", "failed", "offers", "tags"}
+//
+// The cheapest, fastest and best tags are only known once every search is
+// done, so they come in the done event as {"batch", "id"} of each offer.
+func searchStreamHandler(c *gin.Context) {
+	var params searchParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
-	req.Header.Set("Authorization", "Bearer "+accessToken)
-	resp, err := client.Do(req)
+	searchOptions, err := searchOptionsFromQuery(c)
 	if err != nil {
-		log.Fatal(err)
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
-	defer resp.Body.Close()
-
-	var orderResponse OrderResponse
-	err = json.NewDecoder(resp.Body).Decode(&orderResponse)
+	weights, err := scoreWeights(c.Query("weights"))
 	if err != nil {
-		fmt.Println("Error decoding flight search response:", err)
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	c.IndentedJSON(http.StatusCreated, orderResponse)
+	var batches []searchBatch
+	for _, date := range strings.Split(params.FechaSalida, ",") {
+		date = strings.TrimSpace(date)
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("fecha %q is not a YYYY-MM-DD date", date)})
+			return
+		}
+		batch := searchBatch{Name: date, Params: params}
+		batch.Params.FechaSalida = date
+		batches = append(batches, batch)
+	}
+	if len(batches) > maxStreamDates {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("at most %d dates can be searched at once", maxStreamDates)})
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	// Keeps proxies such as nginx from buffering the stream.
+	c.Header("X-Accel-Buffering", "no")
+	send := func(event string, data any) {
+		c.SSEvent(event, data)
+		c.Writer.Flush()
+	}
+	send("progress", gin.H{"batch": "", "completed": 0, "total": len(batches)})
+
+	type ref struct {
+		Batch string `json:"batch"`
+		ID    string `json:"id"`
+	}
+	var refs []ref
+	var summaries []search.Offer
+	completed, failed := 0, 0
+	for batch := range streamOffers(c.Request.Context(), batches, searchOptions) {
+		completed++
+		if batch.Err != nil {
+			failed++
+			status := http.StatusBadRequest
+			var inputErr *inputError
+			if !errors.As(batch.Err, &inputErr) {
+				status = upstreamStatus(batch.Err)
+			}
+			event := gin.H{"batch": batch.Name, "status": status, "error": batch.Err.Error()}
+			var providerErr *providerError
+			if errors.As(batch.Err, &providerErr) && providerErr.Body != nil {
+				event["details"] = providerErr.Body
+			}
+			send("error", event)
+		} else if len(batch.Offers.Data) > 0 {
+			send("offers", gin.H{"batch": batch.Name, "offers": batch.Offers.Data})
+			for _, offer := range batch.Offers.Data {
+				refs = append(refs, ref{batch.Name, offer.ID})
+			}
+			summaries = append(summaries, batch.summaries...)
+		}
+		send("progress", gin.H{"batch": batch.Name, "completed": completed, "total": len(batches)})
+	}
+	if c.Request.Context().Err() != nil {
+		// The client is gone.
+		return
+	}
+
+	tagged := map[string]ref{}
+	_, tags := search.Score(summaries, weights)
+	for i, offerTags := range tags {
+		for _, tag := range offerTags {
+			tagged[tag] = refs[i]
+		}
+	}
+	send("done", gin.H{"batches": len(batches), "failed": failed, "offers": len(refs), "tags": tagged})
 }
 
-func main() {
+// fareRulesSummary parses the detailed fare rules included in a pricing
+// response, ordered by segment.
+func fareRulesSummary(pricingResponse PricingResponse) []farerules.Rules {
+	var summary []farerules.Rules
+	for _, rule := range pricingResponse.Included.DetailedFareRules {
+		summary = append(summary, farerules.Parse(rule.SegmentID, rule.FareBasis, rule.Name, rule.FareNotes.Descriptions))
+	}
+	sort.Slice(summary, func(i, j int) bool {
+		a, b := summary[i].SegmentID, summary[j].SegmentID
+		// Segment IDs are numeric strings; compare "2" before "10".
+		return len(a) < len(b) || len(a) == len(b) && a < b
+	})
+	return summary
+}
+
+// publishPriceChanges emits a price.changed event for every offer whose
+// priced grand total differs from the one returned by the search.
+func publishPriceChanges(ctx context.Context, searchPrice FlightPriceRequest, pricingResponse PricingResponse) {
+	for _, priced := range pricingResponse.Data.FlightOffers {
+		for _, searched := range searchPrice.Data.FlightOffers {
+			if searched.ID != priced.ID || searched.Price.GrandTotal == "" || searched.Price.GrandTotal == priced.Price.GrandTotal {
+				continue
+			}
+			err := webhooks.Publish(ctx, webhook.EventPriceChanged, gin.H{
+				"offerId":            priced.ID,
+				"currency":           priced.Price.Currency,
+				"previousGrandTotal": searched.Price.GrandTotal,
+				"grandTotal":         priced.Price.GrandTotal,
+			})
+			if err != nil {
+				slog.ErrorContext(ctx, "publishing webhook event failed", "event", webhook.EventPriceChanged, "error", err)
+			}
+		}
+	}
+}
+
+func priceHandler(c *gin.Context) { // function that handles the request
+
+	var searchPrice FlightPriceRequest
+	if err := c.BindJSON(&searchPrice); err != nil {
+		return
+	}
+
+	pricingResponse, err := priceOffers(c.Request.Context(), searchPrice, c.Query("fareRules") == "true", c.Query("moneda"))
+	if err != nil {
+		serviceFailed(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusCreated, pricingResponse)
+}
+
+// recordBookingCreated adds the priced and created transitions of a new
+// booking to its timeline.
+func recordBookingCreated(ctx context.Context, bookingRequest BookingRequest, bookingResponse BookingResponse) {
+	orderID := bookingResponse.Data.ID
+	for _, offer := range bookingRequest.Data.FlightOffers {
+		bookingHistory.Record(ctx, history.Event{
+			OrderID: orderID,
+			Type:    history.TypePriced,
+			Details: map[string]any{
+				"offerId":           offer.ID,
+				"currency":          offer.Price.Currency,
+				"grandTotal":        offer.Price.GrandTotal,
+				"lastTicketingDate": offer.LastTicketingDate,
+			},
+		})
+	}
+	bookingHistory.Record(ctx, history.Event{
+		OrderID: orderID,
+		Type:    history.TypeCreated,
+		Details: map[string]any{"travelers": len(bookingRequest.Data.Travelers)},
+	})
+}
+
+func bookingHandler(c *gin.Context) {
+
+	var bookingRequest BookingRequest
+	if err := c.BindJSON(&bookingRequest); err != nil {
+		return
+	}
+
+	lang := c.Query("lang")
+	if lang == "" {
+		lang = c.GetHeader("Accept-Language")
+	}
+	bookingResponse, err := createBooking(c.Request.Context(), bookingRequest, lang)
+	if err != nil {
+		serviceFailed(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusCreated, bookingResponse)
+}
+
+// getOrder retrieves a flight order from Amadeus.
+func getOrder(ctx context.Context, accessToken, orderID string) (OrderResponse, error) {
+	url := cfg.Amadeus.URL("/v1/booking/flight-orders/" + orderID)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return OrderResponse{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	resp, err := amadeus.Do(upstream.WithOperation(req, "flight-orders.get"))
+	if err != nil {
+		bookingHistory.OrderCall(ctx, orderID, "flight-orders.get", 0, err)
+		return OrderResponse{}, err
+	}
+	defer resp.Body.Close()
+	if err := checkAmadeusResponse(resp); err != nil {
+		bookingHistory.OrderCall(ctx, orderID, "flight-orders.get", resp.StatusCode, err)
+		return OrderResponse{}, err
+	}
+
+	var orderResponse OrderResponse
+	err = json.NewDecoder(resp.Body).Decode(&orderResponse)
+	bookingHistory.OrderCall(ctx, orderID, "flight-orders.get", resp.StatusCode, err)
+	return orderResponse, err
+}
+
+func orderHandler(c *gin.Context) { // function that handles the request
+	var orderID OrderSearch
+	if err := bindBodyOrQuery(c, &orderID); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	orderResponse, err := retrieveOrder(c.Request.Context(), orderID.OrderID, orderID.Moneda)
+	if err != nil {
+		serviceFailed(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusCreated, orderResponse)
+}
+
+// itineraryCalendar builds a calendar with one event per flight segment of
+// an order.
+func itineraryCalendar(orderResponse OrderResponse) (ical.Calendar, error) {
+	orderID := orderResponse.Data.ID
+	calendar := ical.Calendar{Name: "goTravel " + orderID}
+	for _, offer := range orderResponse.Data.FlightOffers {
+		for _, itinerary := range offer.Itineraries {
+			for _, segment := range itinerary.Segments {
+				departure, departureZone, err := ical.LocalTime(segment.Departure.IataCode, segment.Departure.At)
+				if err != nil {
+					return ical.Calendar{}, err
+				}
+				arrival, arrivalZone, err := ical.LocalTime(segment.Arrival.IataCode, segment.Arrival.At)
+				if err != nil {
+					return ical.Calendar{}, err
+				}
+				flight := segment.CarrierCode + segment.Number
+				location := segment.Departure.IataCode
+				if segment.Departure.Terminal != "" {
+					location += " Terminal " + segment.Departure.Terminal
+				}
+				description := fmt.Sprintf("Vuelo %s operado por %s\nSalida: %s\nLlegada: %s\nReserva: %s",
+					flight, segment.Operating.CarrierCode, terminalName(segment.Departure.IataCode, segment.Departure.Terminal),
+					terminalName(segment.Arrival.IataCode, segment.Arrival.Terminal), orderID)
+				// Without both zones the event is written in local times,
+				// which calendars show in the reader's own zone.
+				if !departureZone || !arrivalZone {
+					unknown := segment.Departure.IataCode
+					if departureZone {
+						unknown = segment.Arrival.IataCode
+					} else if !arrivalZone {
+						unknown += ", " + segment.Arrival.IataCode
+					}
+					slog.Warn("airport time zone unknown, writing local times", "airports", unknown, "orderId", orderID, "segmentId", segment.ID)
+					description += "\nHorarios en hora local de cada aeropuerto (zona horaria desconocida para " + unknown + ")"
+				}
+				calendar.Events = append(calendar.Events, ical.Event{
+					UID:         orderID + "-" + segment.ID + "@gotravel",
+					Summary:     fmt.Sprintf("Vuelo %s %s → %s", flight, segment.Departure.IataCode, segment.Arrival.IataCode),
+					Description: description,
+					Location:    location,
+					Start:       departure,
+					End:         arrival,
+				})
+			}
+		}
+	}
+	return calendar, nil
+}
+
+func terminalName(iata, terminal string) string {
+	if terminal == "" {
+		return iata
+	}
+	return iata + " terminal " + terminal
+}
+
+// itineraryHandler returns an order as an iCalendar file.
+func itineraryHandler(c *gin.Context) {
+	orderResponse, err := retrieveOrder(c.Request.Context(), c.Param("id"), "")
+	if err != nil {
+		upstreamFailed(c, err)
+		return
+	}
+	if orderResponse.Data.ID == "" {
+		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "order not found"})
+		return
+	}
+	calendar, err := itineraryCalendar(orderResponse)
+	if err != nil {
+		c.IndentedJSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+
+	var ics bytes.Buffer
+	if err := calendar.Write(&ics); err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	filename := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, orderResponse.Data.ID)
+	c.Header("Content-Disposition", `attachment; filename="gotravel-`+filename+`.ics"`)
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", ics.Bytes())
+}
+
+// cheapestPrice searches the route of a price watch and returns the lowest
+// offer, converted to the watch's currency when it differs from billing.
+func cheapestPrice(ctx context.Context, w watch.Watch) (money.Money, error) {
+	offers, err := searchFlights(ctx, searchParams{
+		Origen:      w.Origin,
+		Destino:     w.Destination,
+		FechaSalida: w.DepartureDate,
+		Adultos:     strconv.Itoa(w.Adults),
+	})
+	if err != nil {
+		return money.Money{}, err
+	}
+	var cheapest money.Money
+	found := false
+	for _, summary := range summarizeOffers(offers) {
+		if summary.Incomplete {
+			continue
+		}
+		if cmp, err := summary.Price.Cmp(cheapest); !found || err == nil && cmp < 0 {
+			cheapest, found = summary.Price, true
+		}
+	}
+	if !found {
+		return money.Money{}, fmt.Errorf("no priced offers for %s-%s on %s", w.Origin, w.Destination, w.DepartureDate)
+	}
+	if cheapest.Currency() == w.Currency {
+		return cheapest, nil
+	}
+	if err := ensureRates(ctx); err != nil {
+		return money.Money{}, err
+	}
+	converted, err := rates.Convert(cheapest, w.Currency)
+	if err != nil {
+		return money.Money{}, err
+	}
+	return converted.Amount, nil
+}
+
+// watchOwner authenticates the price-watch routes: the bearer token must be
+// one of cfg.Watches.APIKeys, whose owner is then the only one who sees the
+// watches created with it.
+func watchOwner(c *gin.Context) {
+	owners, _ := cfg.Watches.Owners() // validated by config.Load
+	given := []byte(strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer "))
+	owner := ""
+	for key, name := range owners {
+		if subtle.ConstantTimeCompare(given, []byte(key)) == 1 {
+			owner = name
+		}
+	}
+	if owner == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	c.Set(watchOwnerKey, owner)
+	c.Next()
+}
+
+const watchOwnerKey = "watchOwner"
+
+func createWatchHandler(c *gin.Context) {
+	owner := c.GetString(watchOwnerKey)
+	var priceWatch watch.Watch
+	if err := c.BindJSON(&priceWatch); err != nil {
+		return
+	}
+	if priceWatch.Currency == "" {
+		priceWatch.Currency = cfg.Search.Currency
+	}
+	if err := priceWatch.Validate(); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	owned, err := watches.Owned(c.Request.Context(), owner)
+	if err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if len(owned) >= cfg.Watches.MaxPerOwner {
+		c.IndentedJSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("at most %d price watches per owner", cfg.Watches.MaxPerOwner)})
+		return
+	}
+	priceWatch = watch.Watch{
+		ID:            ids.New(),
+		Owner:         owner,
+		Origin:        priceWatch.Origin,
+		Destination:   priceWatch.Destination,
+		DepartureDate: priceWatch.DepartureDate,
+		Adults:        priceWatch.Adults,
+		Threshold:     priceWatch.Threshold,
+		Currency:      priceWatch.Currency,
+		NotifyURL:     priceWatch.NotifyURL,
+		CreatedAt:     time.Now().UTC(),
+	}
+	if err := watches.Insert(c.Request.Context(), priceWatch); err != nil {
+		slog.ErrorContext(c.Request.Context(), "saving price watch failed", "error", err)
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.IndentedJSON(http.StatusCreated, priceWatch)
+}
+
+func listWatchesHandler(c *gin.Context) {
+	list, err := watches.Owned(c.Request.Context(), c.GetString(watchOwnerKey))
+	if err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, list)
+}
+
+func deleteWatchHandler(c *gin.Context) {
+	found, err := watches.Delete(c.Request.Context(), c.Param("id"), c.GetString(watchOwnerKey))
+	if err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !found {
+		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "price watch not found"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// mailSender returns the configured SMTP sender, or nil when no SMTP host is
+// configured and confirmation emails are disabled.
+func mailSender() mailer.Sender {
+	if cfg.SMTP.Host == "" {
+		return nil
+	}
+	return mailer.SMTPSender{
+		Host:     cfg.SMTP.Host,
+		Port:     cfg.SMTP.Port,
+		Username: cfg.SMTP.Username,
+		Password: cfg.SMTP.Password.Reveal(),
+		From:     cfg.SMTP.From,
+	}
+}
+
+// sendConfirmation emails the booking confirmation to every traveler with an
+// email address. It runs in the background, so errors are only logged.
+func sendConfirmation(ctx context.Context, lang string, bookingRequest BookingRequest, bookingResponse BookingResponse) {
+	sender := mailSender()
+	if sender == nil {
+		return
+	}
+	locale := "es-CL"
+	if lang == "en" {
+		locale = "en"
+	}
+
+	confirmation := mailer.Confirmation{OrderID: bookingResponse.Data.ID}
+	var recipients []string
+	for _, traveler := range bookingRequest.Data.Travelers {
+		confirmation.Travelers = append(confirmation.Travelers, traveler.Name.FirstName+" "+traveler.Name.LastName)
+		if email := traveler.Contact.EmailAddress; email != "" && !slices.Contains(recipients, email) {
+			recipients = append(recipients, email)
+		}
+	}
+	var prices []money.Money
+	for _, offer := range bookingRequest.Data.FlightOffers {
+		for _, itinerary := range offer.Itineraries {
+			for _, segment := range itinerary.Segments {
+				confirmation.Segments = append(confirmation.Segments, mailer.Segment{
+					Flight:    segment.CarrierCode + segment.Number,
+					From:      segment.Departure.IataCode,
+					To:        segment.Arrival.IataCode,
+					Departure: mailer.FormatTime(lang, segment.Departure.At),
+					Arrival:   mailer.FormatTime(lang, segment.Arrival.At),
+					Terminal:  segment.Arrival.Terminal,
+				})
+			}
+		}
+		if price, err := money.Parse(offer.Price.GrandTotal, offer.Price.Currency); err == nil {
+			prices = append(prices, price)
+		}
+	}
+	if total, err := money.Sum(prices...); err == nil {
+		confirmation.Price = total.Format(locale)
+	}
+
+	msg, err := mailer.Render(lang, confirmation)
+	if err != nil {
+		slog.ErrorContext(ctx, "rendering confirmation email failed", "orderId", bookingResponse.Data.ID, "error", err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, cfg.SMTP.Timeout)
+	defer cancel()
+	if err := sender.Send(ctx, recipients, msg); err != nil {
+		slog.ErrorContext(ctx, "sending confirmation email failed", "orderId", bookingResponse.Data.ID, "error", err)
+		return
+	}
+	slog.InfoContext(ctx, "confirmation email sent", "orderId", bookingResponse.Data.ID, "recipients", len(recipients))
+}
+
+// cancelBookingHandler cancels a flight order with its provider. A rejected
+// cancellation is answered with the status and errors of the provider.
+func cancelBookingHandler(c *gin.Context) {
+	err := cancelBooking(c.Request.Context(), c.Param("id"))
+	var providerErr *providerError
+	if errors.As(err, &providerErr) {
+		c.IndentedJSON(providerErr.Status, ErrorResponse{Error: "cancellation failed", Details: providerErr.Body})
+		return
+	}
+	if err != nil {
+		upstreamFailed(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// bookingEventsHandler returns the timeline of a booking.
+func bookingEventsHandler(c *gin.Context) {
+	timeline, err := bookingHistory.Timeline(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if len(timeline.Events) == 0 {
+		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "no events for this booking"})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, timeline)
+}
+
+func createWebhookHandler(c *gin.Context) {
+	var sub webhook.Subscription
+	if err := c.BindJSON(&sub); err != nil {
+		return
+	}
+	if err := sub.Validate(); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	sub.ID = ids.New()
+	sub.CreatedAt = time.Now().UTC()
+
+	if err := webhooks.Store.InsertSubscription(c.Request.Context(), sub); err != nil {
+		slog.ErrorContext(c.Request.Context(), "saving webhook subscription failed", "error", err)
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	// The secret is only shown once, when the subscription is created.
+	c.IndentedJSON(http.StatusCreated, sub)
+}
+
+func listWebhooksHandler(c *gin.Context) {
+	subscriptions, err := webhooks.Store.Subscriptions(c.Request.Context())
+	if err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	for i := range subscriptions {
+		subscriptions[i].Secret = ""
+	}
+	c.IndentedJSON(http.StatusOK, subscriptions)
+}
 
-	router := gin.Default()
+func deleteWebhookHandler(c *gin.Context) {
+	found, err := webhooks.Store.DeleteSubscription(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !found {
+		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "webhook not found"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func listDeliveriesHandler(c *gin.Context) {
+	limit, err := strconv.ParseInt(c.DefaultQuery("limit", "50"), 10, 64)
+	if err != nil || limit < 1 {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+		return
+	}
+	deliveries, err := webhooks.Store.Deliveries(c.Request.Context(), limit)
+	if err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, deliveries)
+}
+
+func replayDeliveryHandler(c *gin.Context) {
+	delivery, err := webhooks.Replay(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, webhook.ErrNotFound) {
+		c.IndentedJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, delivery)
+}
+
+// adminOnly rejects requests that do not carry the admin bearer token.
+// Admin routes are disabled when no admin token is configured.
+func adminOnly(c *gin.Context) {
+	token := cfg.AdminToken.Reveal()
+	given := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if token == "" || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	c.Next()
+}
+
+// getRatesHandler returns the exchange-rate table currently in use.
+func getRatesHandler(c *gin.Context) {
+	if err := ensureRates(c.Request.Context()); err != nil {
+		c.IndentedJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	table, _ := rates.Table()
+	c.IndentedJSON(http.StatusOK, table)
+}
+
+// updateRatesHandler stores a new exchange-rate table and starts using it.
+func updateRatesHandler(c *gin.Context) {
+	var table currency.Table
+	if err := c.BindJSON(&table); err != nil {
+		return
+	}
+	if err := table.Validate(); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	table.UpdatedAt = time.Now().UTC()
+
+	if err := storeRates(c.Request.Context(), table); err != nil {
+		slog.ErrorContext(c.Request.Context(), "saving exchange rates failed", "error", err)
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.IndentedJSON(http.StatusCreated, table)
+}
+
+// providerError is an unsuccessful response from a flight provider. Body is
+// its errors document.
+type providerError struct {
+	Provider string
+	Status   int
+	Body     json.RawMessage
+}
+
+func (e *providerError) Error() string {
+	return fmt.Sprintf("%s responded %d %s", e.Provider, e.Status, http.StatusText(e.Status))
+}
+
+// checkAmadeusResponse returns a *providerError, consuming the body, when
+// resp is not successful.
+func checkAmadeusResponse(resp *http.Response) error {
+	return checkProviderResponse(config.ProviderAmadeus, resp)
+}
+
+func checkProviderResponse(provider string, resp *http.Response) error {
+	if resp.StatusCode < 300 {
+		return nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if !json.Valid(body) {
+		body = nil
+	}
+	return &providerError{Provider: provider, Status: resp.StatusCode, Body: body}
+}
+
+// serviceFailed answers a request whose operation failed: 400 for invalid
+// input and the upstream status otherwise.
+func serviceFailed(c *gin.Context, err error) {
+	var inputErr *inputError
+	if errors.As(err, &inputErr) {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	upstreamFailed(c, err)
+}
+
+// upstreamFailed answers a request whose upstream call failed. The errors
+// returned by the provider are passed on as details.
+func upstreamFailed(c *gin.Context, err error) {
+	response := ErrorResponse{Error: err.Error()}
+	var providerErr *providerError
+	if errors.As(err, &providerErr) {
+		response.Details = providerErr.Body
+	}
+	c.IndentedJSON(upstreamStatus(err), response)
+}
+
+// upstreamStatus is the status returned when a call to an upstream fails:
+// 503 while its circuit breaker is open or no exchange rates are loaded, 504
+// when it timed out and 502 otherwise. A provider rejecting the request
+// itself (400, 404 or 422) is passed on, since the client has to change it.
+func upstreamStatus(err error) int {
+	var providerErr *providerError
+	if errors.As(err, &providerErr) {
+		switch providerErr.Status {
+		case http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity:
+			return providerErr.Status
+		}
+		return http.StatusBadGateway
+	}
+	if errors.Is(err, upstream.ErrCircuitOpen) || errors.Is(err, currency.ErrNoRates) {
+		return http.StatusServiceUnavailable
+	}
+	if errors.Is(err, context.DeadlineExceeded) || os.IsTimeout(err) {
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusBadGateway
+}
+
+// readiness checks the dependencies a request needs. main applies the
+// configured cache and timeout.
+var readiness = &health.Checker{
+	Checks: []health.Check{
+		{Name: "mongo", Func: checkMongo},
+		{Name: "amadeusToken", Func: checkToken},
+		{Name: "amadeus", Func: checkAmadeus},
+	},
+	TTL:     cfg.Readiness.CacheTTL,
+	Timeout: cfg.Readiness.CheckTimeout,
+}
+
+// mongoProbe is the client checkMongo pings. It stays connected between
+// checks, so an uncached probe costs a round trip instead of a new
+// connection.
+var mongoProbe struct {
+	sync.Mutex
+	client *mongo.Client
+}
+
+func checkMongo(ctx context.Context) error {
+	mongoProbe.Lock()
+	defer mongoProbe.Unlock()
+	if mongoProbe.client == nil {
+		client, err := connectToMongoDB(ctx)
+		if err != nil {
+			return err
+		}
+		mongoProbe.client = client
+		return nil
+	}
+	return mongoProbe.client.Database("admin").RunCommand(ctx, bson.D{{Key: "ping", Value: 1}}).Err()
+}
+
+// closeMongoProbe disconnects the client kept by checkMongo.
+func closeMongoProbe() {
+	mongoProbe.Lock()
+	defer mongoProbe.Unlock()
+	if mongoProbe.client != nil {
+		closeMongoDBConnection(mongoProbe.client)
+		mongoProbe.client = nil
+	}
+}
+
+func checkToken(ctx context.Context) error {
+	_, err := requestToken(ctx)
+	return err
+}
+
+// checkAmadeus fails while the circuit breaker is open or when the API host
+// does not answer. Any response below 500 counts as reachable.
+func checkAmadeus(ctx context.Context) error {
+	if amadeus.Breaker.Open() {
+		return upstream.ErrCircuitOpen
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", cfg.Amadeus.URL("/v1/security/oauth2/token"), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := amadeusClient.Do(upstream.WithOperation(req, "healthcheck"))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("amadeus responded %s", resp.Status)
+	}
+	return nil
+}
+
+// healthzHandler reports that the process is alive.
+func healthzHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": health.StatusOK})
+}
+
+// readyzHandler reports whether the server can serve requests, with the
+// status of every dependency.
+func readyzHandler(c *gin.Context) {
+	report := readiness.Report(c.Request.Context())
+	status := http.StatusOK
+	if !report.Ready() {
+		status = http.StatusServiceUnavailable
+	}
+	c.IndentedJSON(status, report)
+}
+
+// apiSpec documents every route registered by setupRouter. Schemas are
+// generated from the request and response types.
+func apiSpec() *openapi.Document {
+	doc := openapi.New(openapi.Info{
+		Title:       "goTravel API",
+		Description: "Búsqueda, cotización y reserva de vuelos sobre Amadeus Self-Service y, si se configura, Duffel.",
+		Version:     "1.0.0",
+	})
+	doc.Tags = []openapi.Tag{
+		{Name: "vuelos", Description: "Búsqueda, cotización y reservas."},
+		{Name: "alertas", Description: "Alertas de precio revisadas periódicamente. Requieren una clave de WATCH_API_KEYS; cada clave ve solo sus alertas."},
+		{Name: "admin", Description: "Requiere el token ADMIN_TOKEN."},
+		{Name: "operación", Description: "Sondas, métricas y esta documentación."},
+	}
+	doc.Components.SecuritySchemes["adminToken"] = &openapi.SecurityScheme{
+		Type:        "http",
+		Scheme:      "bearer",
+		Description: "El valor de ADMIN_TOKEN.",
+	}
+	doc.Components.SecuritySchemes["watchKey"] = &openapi.SecurityScheme{
+		Type:        "http",
+		Scheme:      "bearer",
+		Description: "Una de las claves de WATCH_API_KEYS.",
+	}
+	doc.Define(money.Money{}, &openapi.Schema{
+		Type: "object",
+		Properties: map[string]*openapi.Schema{
+			"amount":   {Type: "string", Example: "125990"},
+			"currency": {Type: "string", Example: "CLP"},
+		},
+		Required: []string{"amount", "currency"},
+	})
+
+	str := func(name, in, description string) openapi.Parameter {
+		return openapi.Parameter{Name: name, In: in, Description: description, Schema: &openapi.Schema{Type: "string"}}
+	}
+	required := func(p openapi.Parameter) openapi.Parameter {
+		p.Required = true
+		return p
+	}
+	errorResponses := func(codes ...string) map[string]*openapi.Response {
+		descriptions := map[string]string{
+			"400": "Parámetros inválidos. Si los rechazó Amadeus, sus errores van en details.",
+			"401": "Falta el token o no es válido.",
+			"404": "No encontrado.",
+			"409": "Se alcanzó el máximo de alertas por clave.",
+			"422": "Amadeus no pudo procesar la solicitud; sus errores van en details.",
+			"500": "Error interno.",
+			"502": "Amadeus respondió con un error.",
+			"503": "Amadeus no está disponible (circuito abierto).",
+			"504": "Amadeus no respondió a tiempo.",
+		}
+		responses := map[string]*openapi.Response{}
+		for _, code := range codes {
+			responses[code] = &openapi.Response{Description: descriptions[code], Content: doc.JSON(ErrorResponse{})}
+		}
+		return responses
+	}
+	with := func(responses map[string]*openapi.Response, code, description string, body any) map[string]*openapi.Response {
+		response := &openapi.Response{Description: description}
+		if body != nil {
+			response.Content = doc.JSON(body)
+		}
+		responses[code] = response
+		return responses
+	}
+	upstreamErrors := []string{"400", "404", "422", "502", "503", "504"}
+	admin := []map[string][]string{{"adminToken": {}}}
+	watchKey := []map[string][]string{{"watchKey": {}}}
+	moneda := str("moneda", "query", "Moneda en que mostrar además los precios (displayPrice).")
+
+	doc.Add("GET", "/api/search", &openapi.Operation{
+		OperationID: "searchFlights",
+		Summary:     "Buscar vuelos",
+		Description: "Los criterios van en la query. Por compatibilidad también se aceptan como cuerpo JSON con los mismos nombres. Se consulta a la vez a cada proveedor configurado; un itinerario que ofrecen varios se deja sólo del que lo vende más barato, y provider indica de cuál viene cada oferta.",
+		Tags:        []string{"vuelos"},
+		Parameters: []openapi.Parameter{
+			required(str("origen", "query", "Código IATA de origen.")),
+			required(str("destino", "query", "Código IATA de destino.")),
+			required(str("fecha", "query", "Fecha de salida (AAAA-MM-DD).")),
+			required(str("adultos", "query", "Cantidad de adultos.")),
+			moneda,
+			str("sort", "query", "price, duration, departure, arrival o stops; con \"-\" para orden descendente."),
+			str("carrier", "query", "Aerolíneas separadas por coma."),
+			str("departAfter", "query", "Hora mínima de salida (HH:MM)."),
+			str("departBefore", "query", "Hora máxima de salida (HH:MM)."),
+			str("maxDuration", "query", "Duración máxima, ISO 8601 (PT6H)."),
+			str("maxPrice", "query", "Precio máximo en la moneda de facturación."),
+			str("bags", "query", "true para sólo ofertas con equipaje incluido."),
+			str("limit", "query", "Tamaño de página."),
+			str("offset", "query", "Desplazamiento de la página."),
+			str("cursor", "query", "nextCursor de la página anterior."),
+			str("weights", "query", "Pesos del puntaje \"best\", por ejemplo price=0.6,duration=0.4."),
+		},
+		Responses: with(errorResponses(upstreamErrors...), "201", "Ofertas encontradas.", FlighOffers{}),
+	})
+	var streamParameters []openapi.Parameter
+	for _, p := range doc.Paths["/api/search"]["get"].Parameters {
+		switch p.Name {
+		case "limit", "offset", "cursor":
+			continue
+		case "fecha":
+			p.Description = fmt.Sprintf("Fechas de salida (AAAA-MM-DD) separadas por coma, hasta %d.", maxStreamDates)
+		}
+		streamParameters = append(streamParameters, p)
+	}
+	doc.Add("GET", "/api/search/stream", &openapi.Operation{
+		OperationID: "streamSearch",
+		Summary:     "Buscar vuelos en varias fechas, con resultados a medida que llegan",
+		Description: "Server-Sent Events. progress informa el avance (la primera vez con completed 0); offers trae las ofertas de una fecha, filtradas y ordenadas como en /api/search pero sin paginar; error, el fallo de una fecha sin cortar las demás; done cierra el stream con los totales y, en tags, la fecha (batch) e id de las ofertas cheapest, fastest y best.",
+		Tags:        []string{"vuelos"},
+		Parameters:  streamParameters,
+		Responses:   with(errorResponses("400"), "200", "Stream de eventos.", nil),
+	})
+	doc.Paths["/api/search/stream"]["get"].Responses["200"].Content = map[string]*openapi.MediaType{
+		"text/event-stream": {Schema: &openapi.Schema{Type: "string"}},
+	}
+	doc.Add("POST", "/api/pricing", &openapi.Operation{
+		OperationID: "priceOffers",
+		Summary:     "Cotizar ofertas",
+		Description: "Cotiza con el proveedor de las ofertas (provider); no se pueden cotizar juntas ofertas de proveedores distintos.",
+		Tags:        []string{"vuelos"},
+		Parameters: []openapi.Parameter{
+			str("fareRules", "query", "true para incluir las reglas tarifarias resumidas."),
+			moneda,
+		},
+		RequestBody: &openapi.RequestBody{Required: true, Content: doc.JSON(FlightPriceRequest{})},
+		Responses:   with(errorResponses(upstreamErrors...), "201", "Ofertas cotizadas.", PricingResponse{}),
+	})
+	doc.Add("POST", "/api/booking", &openapi.Operation{
+		OperationID: "createBooking",
+		Summary:     "Reservar",
+		Description: "Crea la orden con el proveedor de las ofertas y envía la confirmación por correo a los pasajeros. Las órdenes de proveedores distintos de Amadeus llevan su nombre como prefijo, como en duffel:ord_123.",
+		Tags:        []string{"vuelos"},
+		Parameters:  []openapi.Parameter{str("lang", "query", "Idioma del correo (es o en); por defecto según Accept-Language.")},
+		RequestBody: &openapi.RequestBody{Required: true, Content: doc.JSON(BookingRequest{})},
+		Responses:   with(errorResponses(upstreamErrors...), "201", "Reserva creada.", BookingResponse{}),
+	})
+	doc.Add("GET", "/api/booking", &openapi.Operation{
+		OperationID: "getBooking",
+		Summary:     "Consultar una reserva",
+		Description: "Por compatibilidad también se acepta un cuerpo JSON con los mismos nombres.",
+		Tags:        []string{"vuelos"},
+		Parameters:  []openapi.Parameter{required(str("orderID", "query", "ID de la orden.")), moneda},
+		Responses:   with(errorResponses(upstreamErrors...), "201", "La reserva.", OrderResponse{}),
+	})
+	doc.Add("DELETE", "/api/booking/:id", &openapi.Operation{
+		OperationID: "cancelBooking",
+		Summary:     "Cancelar una reserva",
+		Tags:        []string{"vuelos"},
+		Responses:   with(errorResponses(upstreamErrors...), "204", "Reserva cancelada.", nil),
+	})
+	doc.Add("GET", "/api/booking/:id/ics", &openapi.Operation{
+		OperationID: "getBookingCalendar",
+		Summary:     "Itinerario en formato iCalendar",
+		Tags:        []string{"vuelos"},
+		Responses:   with(errorResponses("404", "500", "502", "503", "504"), "200", "Archivo .ics con un evento por vuelo.", nil),
+	})
+	doc.Paths["/api/booking/{id}/ics"]["get"].Responses["200"].Content = map[string]*openapi.MediaType{
+		"text/calendar": {Schema: &openapi.Schema{Type: "string"}},
+	}
+	doc.Add("GET", "/api/booking/:id/events", &openapi.Operation{
+		OperationID: "getBookingEvents",
+		Summary:     "Historial de una reserva",
+		Tags:        []string{"admin"},
+		Security:    admin,
+		Responses:   with(errorResponses("401", "404", "500"), "200", "Eventos de la reserva, del más antiguo al más reciente.", history.Timeline{}),
+	})
+	doc.Add("POST", "/graphql", &openapi.Operation{
+		OperationID: "graphql",
+		Summary:     "Consultas GraphQL",
+		Description: "Búsqueda y consulta de reservas (query), cotización, reserva y cancelación (mutation) con los mismos nombres de campos que Amadeus. Responde 200 aunque fallen; cada error trae en extensions.status el código que respondería la API REST.",
+		Tags:        []string{"vuelos"},
+		RequestBody: &openapi.RequestBody{Required: true, Content: doc.JSON(graphQLRequest{})},
+		Responses:   with(errorResponses("400"), "200", "Resultado de la consulta.", graphQLResponse{}),
+	})
+
+	doc.Add("POST", "/api/watches", &openapi.Operation{
+		OperationID: "createWatch",
+		Summary:     "Crear una alerta de precio",
+		Tags:        []string{"alertas"},
+		Security:    watchKey,
+		RequestBody: &openapi.RequestBody{Required: true, Content: doc.JSON(watch.Watch{})},
+		Responses:   with(errorResponses("400", "401", "409", "500"), "201", "Alerta creada.", watch.Watch{}),
+	})
+	doc.Add("GET", "/api/watches", &openapi.Operation{
+		OperationID: "listWatches",
+		Summary:     "Listar alertas de precio",
+		Tags:        []string{"alertas"},
+		Security:    watchKey,
+		Responses:   with(errorResponses("401", "500"), "200", "Las alertas de la clave.", []watch.Watch{}),
+	})
+	doc.Add("DELETE", "/api/watches/:id", &openapi.Operation{
+		OperationID: "deleteWatch",
+		Summary:     "Eliminar una alerta de precio",
+		Tags:        []string{"alertas"},
+		Security:    watchKey,
+		Responses:   with(errorResponses("401", "404", "500"), "204", "Alerta eliminada.", nil),
+	})
+
+	doc.Add("GET", "/api/admin/rates", &openapi.Operation{
+		OperationID: "getRates",
+		Summary:     "Tabla de tipos de cambio vigente",
+		Tags:        []string{"admin"},
+		Security:    admin,
+		Responses:   with(errorResponses("401", "404"), "200", "La tabla.", currency.Table{}),
+	})
+	doc.Add("POST", "/api/admin/rates", &openapi.Operation{
+		OperationID: "updateRates",
+		Summary:     "Cargar una tabla de tipos de cambio",
+		Tags:        []string{"admin"},
+		Security:    admin,
+		RequestBody: &openapi.RequestBody{Required: true, Content: doc.JSON(currency.Table{})},
+		Responses:   with(errorResponses("400", "401", "500"), "201", "Tabla guardada y en uso.", currency.Table{}),
+	})
+	doc.Add("POST", "/api/admin/webhooks", &openapi.Operation{
+		OperationID: "createWebhook",
+		Summary:     "Suscribir un webhook",
+		Description: "El secreto de firma sólo se muestra en esta respuesta.",
+		Tags:        []string{"admin"},
+		Security:    admin,
+		RequestBody: &openapi.RequestBody{Required: true, Content: doc.JSON(webhook.Subscription{})},
+		Responses:   with(errorResponses("400", "401", "500"), "201", "Suscripción creada.", webhook.Subscription{}),
+	})
+	doc.Add("GET", "/api/admin/webhooks", &openapi.Operation{
+		OperationID: "listWebhooks",
+		Summary:     "Listar webhooks",
+		Tags:        []string{"admin"},
+		Security:    admin,
+		Responses:   with(errorResponses("401", "500"), "200", "Las suscripciones, sin secreto.", []webhook.Subscription{}),
+	})
+	doc.Add("DELETE", "/api/admin/webhooks/:id", &openapi.Operation{
+		OperationID: "deleteWebhook",
+		Summary:     "Eliminar un webhook",
+		Tags:        []string{"admin"},
+		Security:    admin,
+		Responses:   with(errorResponses("401", "404", "500"), "204", "Suscripción eliminada.", nil),
+	})
+	doc.Add("GET", "/api/admin/webhooks/deliveries", &openapi.Operation{
+		OperationID: "listWebhookDeliveries",
+		Summary:     "Registro de entregas de webhooks",
+		Tags:        []string{"admin"},
+		Security:    admin,
+		Parameters:  []openapi.Parameter{str("limit", "query", "Cantidad máxima de entregas (50 por defecto).")},
+		Responses:   with(errorResponses("400", "401", "500"), "200", "Las entregas más recientes.", []webhook.Delivery{}),
+	})
+	doc.Add("POST", "/api/admin/webhooks/deliveries/:id/replay", &openapi.Operation{
+		OperationID: "replayWebhookDelivery",
+		Summary:     "Reenviar una entrega",
+		Tags:        []string{"admin"},
+		Security:    admin,
+		Responses:   with(errorResponses("401", "404", "500"), "200", "La entrega tras el nuevo intento.", webhook.Delivery{}),
+	})
+
+	doc.Add("GET", "/healthz", &openapi.Operation{
+		OperationID: "healthz",
+		Summary:     "El proceso está vivo",
+		Tags:        []string{"operación"},
+		Responses:   with(map[string]*openapi.Response{}, "200", "Siempre ok.", gin.H{}),
+	})
+	doc.Add("GET", "/readyz", &openapi.Operation{
+		OperationID: "readyz",
+		Summary:     "El servidor puede atender solicitudes",
+		Tags:        []string{"operación"},
+		Responses: with(with(map[string]*openapi.Response{},
+			"200", "Todas las dependencias responden.", health.Report{}),
+			"503", "Alguna dependencia no responde o el servidor se está deteniendo.", health.Report{}),
+	})
+	doc.Add("GET", "/metrics", &openapi.Operation{
+		OperationID: "metrics",
+		Summary:     "Métricas en formato Prometheus",
+		Tags:        []string{"operación"},
+		Responses: map[string]*openapi.Response{"200": {
+			Description: "Métricas.",
+			Content:     map[string]*openapi.MediaType{"text/plain": {Schema: &openapi.Schema{Type: "string"}}},
+		}},
+	})
+	doc.Add("GET", "/openapi.json", &openapi.Operation{
+		OperationID: "openapi",
+		Summary:     "Este documento",
+		Tags:        []string{"operación"},
+		Responses:   with(map[string]*openapi.Response{}, "200", "Especificación OpenAPI.", gin.H{}),
+	})
+	doc.Add("GET", "/docs", &openapi.Operation{
+		OperationID: "docs",
+		Summary:     "Documentación navegable",
+		Tags:        []string{"operación"},
+		Responses: map[string]*openapi.Response{"200": {
+			Description: "Página HTML.",
+			Content:     map[string]*openapi.MediaType{"text/html": {Schema: &openapi.Schema{Type: "string"}}},
+		}},
+	})
+	return doc
+}
+
+var (
+	specOnce sync.Once
+	specJSON []byte
+	specErr  error
+)
+
+// openapiHandler serves the OpenAPI document.
+func openapiHandler(c *gin.Context) {
+	specOnce.Do(func() {
+		specJSON, specErr = json.MarshalIndent(apiSpec(), "", "  ")
+	})
+	if specErr != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": specErr.Error()})
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", specJSON)
+}
+
+// docsHandler serves a page that renders the OpenAPI document.
+func docsHandler(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", openapi.DocsPage("/openapi.json"))
+}
+
+// setupRouter registers every route of the API. apiSpec must document
+// each of them; the tests check that both stay in sync.
+func setupRouter() *gin.Engine {
+	router := gin.New()
+	// Probes are registered before the middleware so they stay out of the
+	// logs, metrics and traces.
+	router.GET("/healthz", healthzHandler)
+	router.GET("/readyz", readyzHandler)
+	router.Use(otelgin.Middleware(tracing.ServiceName), logging.Middleware, logging.Recovery, metrics.Middleware)
+	router.GET("/metrics", metrics.Handler())
 	router.GET("/api/booking", orderHandler)
 	router.GET("/api/search", searchHandler)
+	router.GET("/api/search/stream", searchStreamHandler)
 	router.POST("/api/pricing", priceHandler)
 	router.POST("/api/booking", bookingHandler)
+	router.DELETE("/api/booking/:id", cancelBookingHandler)
+	router.GET("/api/booking/:id/ics", itineraryHandler)
+	// The timeline carries upstream error text, so it is for support only.
+	router.GET("/api/booking/:id/events", adminOnly, bookingEventsHandler)
+	router.POST("/graphql", graphqlHandler)
 
-	err := godotenv.Load("local.env")
+	priceWatches := router.Group("/api/watches", watchOwner)
+	priceWatches.POST("", createWatchHandler)
+	priceWatches.GET("", listWatchesHandler)
+	priceWatches.DELETE("/:id", deleteWatchHandler)
+
+	admin := router.Group("/api/admin", adminOnly)
+	admin.GET("/rates", getRatesHandler)
+	admin.POST("/rates", updateRatesHandler)
+	admin.POST("/webhooks", createWebhookHandler)
+	admin.GET("/webhooks", listWebhooksHandler)
+	admin.DELETE("/webhooks/:id", deleteWebhookHandler)
+	admin.GET("/webhooks/deliveries", listDeliveriesHandler)
+	admin.POST("/webhooks/deliveries/:id/replay", replayDeliveryHandler)
+
+	router.GET("/openapi.json", openapiHandler)
+	router.GET("/docs", docsHandler)
+	return router
+}
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "secrets" {
+		os.Exit(secretsCommand(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
+	loaded, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
 	if err != nil {
-		log.Fatalf("Some error occured. Err: %s", err)
+		slog.Error("loading configuration failed", "error", err)
+		os.Exit(2)
+	}
+	cfg = loaded
+	if cfg.Print {
+		// Secrets are masked by their YAML encoding.
+		yaml.NewEncoder(os.Stdout).Encode(cfg)
+		return
+	}
+	// Traveler data is redacted from every log line; see package logging.
+	slog.SetDefault(logging.New(os.Stdout, logging.ParseLevel(cfg.LogLevel)))
+	slog.Debug("configuration loaded", "config", cfg)
+	router := setupRouter()
+
+	shutdownTracing, err := tracing.Setup(context.Background())
+	if err != nil {
+		slog.Error("setting up tracing failed", "error", err)
+		os.Exit(1)
+	}
+
+	amadeusClient.Timeout = cfg.Amadeus.Timeout
+	var recorder *cassette.Recorder
+	switch {
+	case cfg.Amadeus.Record != "":
+		recorder = &cassette.Recorder{Path: cfg.Amadeus.Record}
+		amadeusClient.Transport = amadeusTransport(recorder)
+		slog.Info("recording Amadeus calls", "cassette", cfg.Amadeus.Record)
+	case len(cfg.Amadeus.Replay) > 0:
+		player, err := cassette.Load(cfg.Amadeus.Replay...)
+		if err != nil {
+			slog.Error("loading cassettes failed", "error", err)
+			os.Exit(1)
+		}
+		amadeusClient.Transport = amadeusTransport(player)
+		slog.Info("replaying Amadeus calls", "cassettes", cfg.Amadeus.Replay, "interactions", player.Len())
 	}
-	server := os.Getenv("SERVER")
-	port := os.Getenv("PORT")
+	amadeus.MaxAttempts = cfg.Amadeus.MaxAttempts
+	amadeus.Breaker.Cooldown = cfg.Amadeus.BreakerCooldown
+	duffelClient.Timeout = cfg.Duffel.Timeout
+	duffel.MaxAttempts = cfg.Duffel.MaxAttempts
+	duffel.Breaker.Cooldown = cfg.Duffel.BreakerCooldown
+	webhooks.Client.Timeout = cfg.Webhooks.Timeout
+	readiness.TTL = cfg.Readiness.CacheTTL
+	readiness.Timeout = cfg.Readiness.CheckTimeout
 
-	router.Run(server + ":" + port)
-	fmt.Println("Server is listening on : " + port)
+	if path := cfg.RatesFile; path != "" {
+		table, err := currency.LoadFile(path)
+		if err != nil {
+			slog.Error("loading exchange rates failed", "path", path, "error", err)
+			os.Exit(1)
+		}
+		// Stored like a table sent to the admin endpoint, so every instance
+		// and every reload uses it.
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.Mongo.Timeout)
+		err = storeRates(ctx, table)
+		cancel()
+		if err != nil {
+			slog.Error("storing exchange rates failed", "path", path, "error", err)
+			os.Exit(1)
+		}
+	}
+	scheduler := &watch.Scheduler{
+		Store:     watches,
+		Search:    cheapestPrice,
+		Notifier:  watch.Notifiers{watch.LogNotifier{}, watch.HTTPNotifier{Client: watch.NewHTTPClient(cfg.Webhooks.Timeout)}},
+		Interval:  cfg.Watches.Interval,
+		Jitter:    cfg.Watches.Jitter,
+		RateLimit: cfg.Watches.RateLimit,
+	}
+	// SIGINT or SIGTERM stop the scheduler and start a graceful shutdown.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	go scheduler.Run(ctx)
+	go func() {
+		// Deliveries whose retries were waiting when the last server stopped.
+		resumeCtx, cancel := context.WithTimeout(ctx, cfg.Mongo.Timeout)
+		defer cancel()
+		if err := webhooks.Resume(resumeCtx); err != nil {
+			slog.Error("resuming webhook deliveries failed", "error", err)
+		}
+	}()
+
+	srv := &http.Server{
+		Addr:              cfg.Server.Addr(),
+		Handler:           router,
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+	}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.ListenAndServe()
+	}()
+	slog.Info("server listening", "addr", srv.Addr)
+
+	var grpcServer *grpc.Server
+	if cfg.Server.GRPCPort != "" {
+		lis, err := net.Listen("tcp", cfg.Server.GRPCAddr())
+		if err != nil {
+			slog.Error("gRPC server failed", "error", err)
+			os.Exit(1)
+		}
+		grpcServer = newGRPCServer()
+		go func() {
+			serveErr <- grpcServer.Serve(lis)
+		}()
+		slog.Info("gRPC server listening", "addr", lis.Addr().String())
+	}
+
+	select {
+	case err := <-serveErr:
+		slog.Error("server failed", "error", err)
+		os.Exit(1)
+	case <-ctx.Done():
+	}
+	stop()
+	// Fail readiness first and give load balancers the drain delay to notice
+	// before the listener closes.
+	readiness.Drain()
+	if delay := cfg.Server.DrainDelay; delay > 0 {
+		slog.Info("draining before shutdown", "delay", delay.String())
+		time.Sleep(delay)
+	}
+	slog.Info("shutting down, waiting for in-flight requests")
+
+	// Requests in flight get until the shutdown timeout to finish.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+	if grpcServer != nil {
+		go func() {
+			// GracefulStop waits for streams too; cut them at the timeout.
+			<-shutdownCtx.Done()
+			grpcServer.Stop()
+		}()
+	}
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Error("shutdown failed", "error", err)
+	}
+	if grpcServer != nil {
+		grpcServer.GracefulStop()
+	}
+	done := make(chan struct{})
+	go func() {
+		background.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-shutdownCtx.Done():
+		slog.Error("shutdown timed out waiting for confirmation emails")
+	}
+	if err := webhooks.Shutdown(shutdownCtx); err != nil {
+		slog.Error("shutdown timed out waiting for webhook deliveries", "error", err)
+	}
+	closeMongoProbe()
+	if recorder != nil {
+		if err := recorder.Close(); err != nil {
+			slog.Error("closing cassette failed", "error", err)
+		}
+	}
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("flushing traces failed", "error", err)
+	}
+	slog.Info("server stopped")
 }
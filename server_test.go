@@ -0,0 +1,265 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"go-quickstart/currency"
+	"go-quickstart/health"
+	"go-quickstart/history"
+	"go-quickstart/openapi"
+	"go-quickstart/watch"
+	"go-quickstart/webhook"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// specPath converts a gin route to an OpenAPI path template.
+func specPath(route string) string {
+	segments := strings.Split(route, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func TestSpecDocumentsEveryRoute(t *testing.T) {
+	doc := apiSpec()
+	routes := map[string]bool{}
+	for _, route := range setupRouter().Routes() {
+		key := strings.ToLower(route.Method) + " " + specPath(route.Path)
+		routes[key] = true
+		if doc.Paths[specPath(route.Path)][strings.ToLower(route.Method)] == nil {
+			t.Errorf("route %s %s is not documented", route.Method, route.Path)
+		}
+	}
+	for path, operations := range doc.Paths {
+		for method := range operations {
+			if !routes[method+" "+path] {
+				t.Errorf("documented operation %s %s has no route", strings.ToUpper(method), path)
+			}
+		}
+	}
+}
+
+func TestSpecOperations(t *testing.T) {
+	doc := apiSpec()
+	ids := map[string]string{}
+	for path, operations := range doc.Paths {
+		for method, op := range operations {
+			where := strings.ToUpper(method) + " " + path
+			if op.OperationID == "" {
+				t.Errorf("%s: missing operationId", where)
+			} else if other, ok := ids[op.OperationID]; ok {
+				t.Errorf("%s: operationId %q already used by %s", where, op.OperationID, other)
+			}
+			ids[op.OperationID] = where
+
+			success := false
+			for code, response := range op.Responses {
+				if code[0] == '2' {
+					success = true
+				}
+				if code[0] >= '4' && strings.HasPrefix(path, "/api/") {
+					if response.Content["application/json"] == nil ||
+						response.Content["application/json"].Schema.Ref != "#/components/schemas/ErrorResponse" {
+						t.Errorf("%s: %s response does not use the error envelope", where, code)
+					}
+				}
+			}
+			if !success {
+				t.Errorf("%s: no success response", where)
+			}
+			if method == "get" && op.RequestBody != nil {
+				t.Errorf("%s: GET operations must not declare a body", where)
+			}
+		}
+	}
+}
+
+func TestSpecReferencesResolve(t *testing.T) {
+	doc := apiSpec()
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tree any
+	if err := json.Unmarshal(raw, &tree); err != nil {
+		t.Fatal(err)
+	}
+	var walk func(v any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			if ref, ok := v["$ref"].(string); ok {
+				name := strings.TrimPrefix(ref, "#/components/schemas/")
+				if doc.Components.Schemas[name] == nil {
+					t.Errorf("unresolved reference %s", ref)
+				}
+			}
+			for _, child := range v {
+				walk(child)
+			}
+		case []any:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+	walk(tree)
+}
+
+// TestSpecMatchesTypes encodes a fully populated value of every documented
+// type and checks that each JSON field it produces is in the schema.
+func TestSpecMatchesTypes(t *testing.T) {
+	doc := apiSpec()
+	for _, v := range []any{
+		FlighOffers{}, FlightPriceRequest{}, PricingResponse{}, BookingRequest{},
+		BookingResponse{}, OrderResponse{}, ErrorResponse{}, history.Timeline{},
+		watch.Watch{}, currency.Table{}, webhook.Subscription{}, webhook.Delivery{},
+		health.Report{},
+	} {
+		name := reflect.TypeOf(v).Name()
+		t.Run(name, func(t *testing.T) {
+			value := reflect.New(reflect.TypeOf(v)).Elem()
+			fill(value, 0)
+			raw, err := json.Marshal(value.Interface())
+			if err != nil {
+				t.Fatal(err)
+			}
+			var encoded any
+			if err := json.Unmarshal(raw, &encoded); err != nil {
+				t.Fatal(err)
+			}
+			checkSchema(t, doc, name, doc.Schema(v), encoded)
+		})
+	}
+}
+
+// fill sets every field, slice and map of v to a non-empty value so that
+// omitempty fields are encoded too.
+func fill(v reflect.Value, depth int) {
+	if depth > 12 {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Pointer:
+		v.Set(reflect.New(v.Type().Elem()))
+		fill(v.Elem(), depth+1)
+	case reflect.Struct:
+		if v.Type().PkgPath() == "time" {
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).CanSet() {
+				fill(v.Field(i), depth+1)
+			}
+		}
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			v.SetBytes([]byte(`"x"`))
+			return
+		}
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		fill(v.Index(0), depth+1)
+	case reflect.Map:
+		v.Set(reflect.MakeMap(v.Type()))
+		key := reflect.New(v.Type().Key()).Elem()
+		fill(key, depth+1)
+		elem := reflect.New(v.Type().Elem()).Elem()
+		fill(elem, depth+1)
+		v.SetMapIndex(key, elem)
+	case reflect.String:
+		v.SetString("x")
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(1)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1)
+	}
+}
+
+func checkSchema(t *testing.T, doc *openapi.Document, path string, schema *openapi.Schema, value any) {
+	t.Helper()
+	if schema.Ref != "" {
+		name := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+		schema = doc.Components.Schemas[name]
+		if schema == nil {
+			t.Fatalf("%s: unresolved reference %s", path, name)
+		}
+	}
+	switch value := value.(type) {
+	case map[string]any:
+		if schema.Type != "object" {
+			if schema.Type != "" {
+				t.Errorf("%s: encoded an object, schema says %s", path, schema.Type)
+			}
+			return
+		}
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if schema.AdditionalProperties != nil {
+				checkSchema(t, doc, path+"."+key, schema.AdditionalProperties, value[key])
+				continue
+			}
+			property, ok := schema.Properties[key]
+			if !ok {
+				t.Errorf("%s: field %q is not in the schema", path, key)
+				continue
+			}
+			checkSchema(t, doc, path+"."+key, property, value[key])
+		}
+	case []any:
+		if schema.Type != "array" {
+			t.Errorf("%s: encoded an array, schema says %q", path, schema.Type)
+			return
+		}
+		for _, item := range value {
+			checkSchema(t, doc, path+"[]", schema.Items, item)
+		}
+	case string:
+		if schema.Type != "" && schema.Type != "string" {
+			t.Errorf("%s: encoded a string, schema says %s", path, schema.Type)
+		}
+	case float64:
+		if schema.Type != "" && schema.Type != "integer" && schema.Type != "number" {
+			t.Errorf("%s: encoded a number, schema says %s", path, schema.Type)
+		}
+	case bool:
+		if schema.Type != "" && schema.Type != "boolean" {
+			t.Errorf("%s: encoded a boolean, schema says %s", path, schema.Type)
+		}
+	}
+}
+
+func TestSummarizeOffersWithoutSegments(t *testing.T) {
+	var offers FlighOffers
+	err := json.Unmarshal([]byte(`{"data": [{
+		"id": "1",
+		"itineraries": [{"duration": "PT2H", "segments": []}],
+		"price": {"currency": "CLP", "total": "100000", "grandTotal": "100000"}
+	}]}`), &offers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	summary := summarizeOffers(offers)[0]
+	if summary.Stops != 0 || !summary.Incomplete {
+		t.Errorf("stops %d, incomplete %v; want 0 stops and an incomplete offer", summary.Stops, summary.Incomplete)
+	}
+}
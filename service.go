@@ -0,0 +1,298 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go-quickstart/currency"
+	"go-quickstart/history"
+	"go-quickstart/mailer"
+	"go-quickstart/metrics"
+	"go-quickstart/search"
+	"go-quickstart/webhook"
+)
+
+// The functions in this file implement the operations offered both by the
+// REST handlers and by the gRPC service, so the two APIs behave the same.
+// They return an inputError for requests the client has to fix and the
+// upstream error otherwise.
+
+// inputError is an error in the request itself.
+type inputError struct {
+	err error
+}
+
+func invalidInput(err error) error { return &inputError{err} }
+
+func (e *inputError) Error() string { return e.err.Error() }
+func (e *inputError) Unwrap() error { return e.err }
+
+// displayPriceError classifies an error of toDisplayPrice: a display
+// currency without a rate is the client's to fix, while missing rates or an
+// unreadable price are the server's.
+func displayPriceError(err error) error {
+	if errors.Is(err, currency.ErrNoRate) {
+		return invalidInput(err)
+	}
+	return err
+}
+
+// searchFilters are the sort, filter and pagination options of a search, as
+// the gRPC and GraphQL APIs take them.
+type searchFilters struct {
+	Sort         string
+	Carriers     []string
+	DepartAfter  string
+	DepartBefore string
+	MaxDuration  string
+	MaxPrice     string
+	BagsIncluded bool
+	Limit        int
+	Offset       int
+	Cursor       string
+}
+
+// options parses the filters as search.ParseOptions parses the query of
+// GET /api/search.
+func (f searchFilters) options() (search.Options, error) {
+	q := url.Values{}
+	set := func(key, value string) {
+		if value != "" {
+			q.Set(key, value)
+		}
+	}
+	set("sort", f.Sort)
+	set("carrier", strings.Join(f.Carriers, ","))
+	set("departAfter", f.DepartAfter)
+	set("departBefore", f.DepartBefore)
+	set("maxDuration", f.MaxDuration)
+	set("maxPrice", f.MaxPrice)
+	set("cursor", f.Cursor)
+	if f.BagsIncluded {
+		q.Set("bags", "true")
+	}
+	if f.Limit != 0 {
+		q.Set("limit", strconv.Itoa(f.Limit))
+	}
+	if f.Offset != 0 {
+		q.Set("offset", strconv.Itoa(f.Offset))
+	}
+	opts, err := search.ParseOptions(q, cfg.Search.Currency)
+	if err != nil {
+		return search.Options{}, invalidInput(err)
+	}
+	return opts, nil
+}
+
+// searchOffers searches flights, scores the offers, keeps the page selected
+// by opts and converts prices to the display currency of params.
+func searchOffers(ctx context.Context, params searchParams, opts search.Options, weights search.Weights) (FlighOffers, error) {
+	offers, err := searchFlights(ctx, params)
+	if err != nil {
+		slog.ErrorContext(ctx, "flight search failed", "error", err)
+		return FlighOffers{}, err
+	}
+	summaries := summarizeOffers(offers)
+	scoreOffers(offers, summaries, weights)
+	offers = paginateOffers(offers, summaries, opts)
+
+	for i := range offers.Data {
+		price := offers.Data[i].Price
+		displayPrice, err := toDisplayPrice(ctx, params.Moneda, price.Currency, price.Total, price.GrandTotal)
+		if err != nil {
+			return FlighOffers{}, displayPriceError(err)
+		}
+		offers.Data[i].DisplayPrice = displayPrice
+	}
+	return offers, nil
+}
+
+// streamConcurrency is the number of upstream searches a streaming search
+// runs at once, to stay within the Amadeus rate limits.
+const streamConcurrency = 4
+
+// searchBatch is one upstream search of a streaming search.
+type searchBatch struct {
+	Name   string
+	Params searchParams
+
+	Offers    FlighOffers
+	summaries []search.Offer
+	Err       error
+}
+
+// streamOffers runs the searches of batches concurrently and sends each one
+// on the returned channel as soon as it completes, with its offers filtered
+// and sorted by opts. Pagination does not apply. The channel is closed once
+// every batch has been sent or ctx is done.
+func streamOffers(ctx context.Context, batches []searchBatch, opts search.Options) <-chan searchBatch {
+	opts.Offset, opts.Limit = 0, search.MaxLimit
+	results := make(chan searchBatch)
+	sem := make(chan struct{}, streamConcurrency)
+	var wg sync.WaitGroup
+	for _, batch := range batches {
+		wg.Add(1)
+		go func(batch searchBatch) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+			batch.Offers, batch.summaries, batch.Err = searchBatchOffers(ctx, batch.Params, opts)
+			select {
+			case results <- batch:
+			case <-ctx.Done():
+			}
+		}(batch)
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+	return results
+}
+
+func searchBatchOffers(ctx context.Context, params searchParams, opts search.Options) (FlighOffers, []search.Offer, error) {
+	offers, err := searchFlights(ctx, params)
+	if err != nil {
+		slog.ErrorContext(ctx, "flight search failed", "departureDate", params.FechaSalida, "error", err)
+		return FlighOffers{}, nil, err
+	}
+	offers = paginateOffers(offers, summarizeOffers(offers), opts)
+	for i := range offers.Data {
+		price := offers.Data[i].Price
+		displayPrice, err := toDisplayPrice(ctx, params.Moneda, price.Currency, price.Total, price.GrandTotal)
+		if err != nil {
+			return FlighOffers{}, nil, displayPriceError(err)
+		}
+		offers.Data[i].DisplayPrice = displayPrice
+	}
+	return offers, summarizeOffers(offers), nil
+}
+
+// priceOffers confirms the price of searched offers with the provider they
+// came from, optionally with the summary of their fare rules, and publishes
+// the price changes.
+func priceOffers(ctx context.Context, searchPrice FlightPriceRequest, withFareRules bool, display string) (PricingResponse, error) {
+	names := make([]string, len(searchPrice.Data.FlightOffers))
+	for i, offer := range searchPrice.Data.FlightOffers {
+		names[i] = offer.Provider
+	}
+	name, provider, err := offersProvider(names)
+	if err != nil {
+		return PricingResponse{}, err
+	}
+	pricingResponse, err := provider.Price(ctx, searchPrice, withFareRules)
+	if err != nil {
+		slog.ErrorContext(ctx, "pricing flight offers failed", "provider", name, "error", err)
+		return PricingResponse{}, err
+	}
+
+	for i := range pricingResponse.Data.FlightOffers {
+		price := pricingResponse.Data.FlightOffers[i].Price
+		billing := price.BillingCurrency
+		if billing == "" {
+			billing = price.Currency
+		}
+		displayPrice, err := toDisplayPrice(ctx, display, billing, price.Total, price.GrandTotal)
+		if err != nil {
+			return PricingResponse{}, displayPriceError(err)
+		}
+		pricingResponse.Data.FlightOffers[i].DisplayPrice = displayPrice
+		pricingResponse.Data.FlightOffers[i].Provider = name
+	}
+	if withFareRules {
+		pricingResponse.FareRules = fareRulesSummary(pricingResponse)
+	}
+	publishPriceChanges(ctx, searchPrice, pricingResponse)
+	return pricingResponse, nil
+}
+
+// createBooking creates a flight order with the provider of its offers,
+// records it and emails the confirmation in lang in the background.
+func createBooking(ctx context.Context, bookingRequest BookingRequest, lang string) (BookingResponse, error) {
+	names := make([]string, len(bookingRequest.Data.FlightOffers))
+	for i, offer := range bookingRequest.Data.FlightOffers {
+		names[i] = offer.Provider
+	}
+	name, provider, err := offersProvider(names)
+	if err != nil {
+		return BookingResponse{}, err
+	}
+	bookingResponse, err := provider.Book(ctx, bookingRequest)
+	if err != nil {
+		return BookingResponse{}, err
+	}
+	if bookingResponse.Data.ID != "" {
+		bookingResponse.Data.ID = orderRef(name, bookingResponse.Data.ID)
+		metrics.BookingCreated()
+		recordBookingCreated(ctx, bookingRequest, bookingResponse)
+		err := webhooks.Publish(ctx, webhook.EventBookingCreated, map[string]any{
+			"orderId":      bookingResponse.Data.ID,
+			"flightOffers": bookingRequest.Data.FlightOffers,
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "publishing webhook event failed", "event", webhook.EventBookingCreated, "error", err)
+		}
+
+		background.Add(1)
+		go func(ctx context.Context) {
+			defer background.Done()
+			sendConfirmation(ctx, mailer.Language(lang), bookingRequest, bookingResponse)
+		}(context.WithoutCancel(ctx))
+	}
+
+	// The order already exists upstream, so answer even if it can't be saved.
+	if err := bookings.Insert(ctx, bookingResponse); err != nil {
+		slog.ErrorContext(ctx, "saving booking failed", "orderId", bookingResponse.Data.ID, "error", err)
+	}
+	return bookingResponse, nil
+}
+
+// retrieveOrder gets a flight order from its provider, with its prices in
+// the display currency.
+func retrieveOrder(ctx context.Context, orderID, display string) (OrderResponse, error) {
+	provider, providerOrderID := orderProvider(orderID)
+	orderResponse, err := provider.Order(ctx, providerOrderID)
+	if err != nil {
+		slog.ErrorContext(ctx, "retrieving order failed", "orderId", orderID, "error", err)
+		return OrderResponse{}, err
+	}
+	if orderResponse.Data.ID != "" {
+		orderResponse.Data.ID = orderID
+	}
+
+	for i := range orderResponse.Data.FlightOffers {
+		price := orderResponse.Data.FlightOffers[i].Price
+		displayPrice, err := toDisplayPrice(ctx, display, price.Currency, price.Total, price.GrandTotal)
+		if err != nil {
+			return OrderResponse{}, displayPriceError(err)
+		}
+		orderResponse.Data.FlightOffers[i].DisplayPrice = displayPrice
+	}
+	return orderResponse, nil
+}
+
+// cancelBooking cancels a flight order with its provider. A rejected
+// cancellation is returned as a *providerError.
+func cancelBooking(ctx context.Context, orderID string) error {
+	provider, providerOrderID := orderProvider(orderID)
+	if err := provider.Cancel(ctx, providerOrderID); err != nil {
+		return err
+	}
+	bookingHistory.Record(ctx, history.Event{OrderID: orderID, Type: history.TypeCancelled})
+	metrics.BookingCancelled()
+
+	err := webhooks.Publish(ctx, webhook.EventBookingCancelled, map[string]any{"orderId": orderID})
+	if err != nil {
+		slog.ErrorContext(ctx, "publishing webhook event failed", "event", webhook.EventBookingCancelled, "error", err)
+	}
+	return nil
+}
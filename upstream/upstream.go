@@ -0,0 +1,289 @@
+// Package upstream wraps an http.Client with retries and a circuit breaker
+// for calls to flight providers.
+//
+// Requests are retried on 429, 5xx and network errors with exponential
+// backoff and jitter, honoring Retry-After. Only idempotent requests get the
+// full treatment: GET, HEAD, OPTIONS, PUT and DELETE, plus requests marked
+// with Idempotent (read-only POSTs such as pricing). Any other request, such
+// as creating a booking, is only retried when the provider could not have
+// acted on it: a 429, or a connection that was never established.
+package upstream
+
+import (
+	"context"
+	"errors"
+	"io"
+	mathrand "math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without calling the provider while the circuit
+// breaker is open.
+var ErrCircuitOpen = errors.New("upstream: provider unavailable, circuit open")
+
+type (
+	idempotentKey struct{}
+	operationKey  struct{}
+)
+
+// Idempotent marks a request as safe to retry even if its method is not.
+func Idempotent(req *http.Request) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), idempotentKey{}, true))
+}
+
+// WithOperation names the provider operation a request performs, such as
+// "flight-orders.create", for Observe.
+func WithOperation(req *http.Request, operation string) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), operationKey{}, operation))
+}
+
+// Operation returns the operation set with WithOperation.
+func Operation(req *http.Request) string {
+	operation, _ := req.Context().Value(operationKey{}).(string)
+	return operation
+}
+
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	marked, _ := req.Context().Value(idempotentKey{}).(bool)
+	return marked
+}
+
+// Client sends requests with retries.
+type Client struct {
+	HTTP *http.Client
+
+	// MaxAttempts per request, including the first one.
+	MaxAttempts int
+	// BaseDelay is the wait before the first retry; it doubles on every
+	// attempt up to MaxDelay, with up to 50% random jitter added. A
+	// Retry-After longer than MaxDelay is not waited for.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+
+	// Breaker is optional.
+	Breaker *Breaker
+
+	// Observe, if set, is called after every attempt with the response
+	// status (0 if there was none), and once with ErrCircuitOpen for calls
+	// rejected by the breaker.
+	Observe func(req *http.Request, status int, err error, elapsed time.Duration)
+}
+
+// Do sends the request, retrying it as described in the package comment.
+// The returned response is the last one received; non-2xx statuses are not
+// errors. Requests with a body must be replayable (http.NewRequest sets
+// GetBody for the usual buffer and reader types).
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	maxAttempts := c.MaxAttempts
+	if maxAttempts < 1 || req.Body != nil && req.GetBody == nil {
+		maxAttempts = 1
+	}
+	for attempt := 1; ; attempt++ {
+		if c.Breaker != nil && !c.Breaker.Allow() {
+			c.observe(req, nil, ErrCircuitOpen, 0)
+			return nil, ErrCircuitOpen
+		}
+		start := time.Now()
+		resp, err := c.send(req, attempt)
+		c.observe(req, resp, err, time.Since(start))
+		if c.Breaker != nil {
+			if req.Context().Err() != nil || permanent(err) {
+				// The caller gave up or the request failed locally; that
+				// says nothing about the provider.
+				c.Breaker.Cancel()
+			} else {
+				c.Breaker.Record(err == nil && resp.StatusCode < 500)
+			}
+		}
+		if attempt == maxAttempts || !c.retryable(req, resp, err) {
+			return resp, err
+		}
+		delay := c.backoff(attempt)
+		if resp != nil {
+			if after, ok := retryAfter(resp); ok {
+				if after > c.maxDelay() {
+					return resp, nil
+				}
+				delay = after
+			}
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (c *Client) observe(req *http.Request, resp *http.Response, err error, elapsed time.Duration) {
+	if c.Observe == nil {
+		return
+	}
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	c.Observe(req, status, err, elapsed)
+}
+
+func (c *Client) send(req *http.Request, attempt int) (*http.Response, error) {
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if attempt > 1 && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = body
+	}
+	return client.Do(req)
+}
+
+func (c *Client) retryable(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+	if err != nil {
+		return !permanent(err) && (idempotent(req) || notSent(err))
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return idempotent(req) && (resp.StatusCode == http.StatusBadGateway ||
+		resp.StatusCode == http.StatusServiceUnavailable ||
+		resp.StatusCode == http.StatusGatewayTimeout ||
+		resp.StatusCode == http.StatusInternalServerError)
+}
+
+// permanent reports whether err says retrying cannot help, by implementing
+// Permanent() bool, as the misses of a replayed cassette do.
+func permanent(err error) bool {
+	var p interface{ Permanent() bool }
+	return errors.As(err, &p) && p.Permanent()
+}
+
+// notSent reports whether err happened before the request could reach the
+// provider.
+func notSent(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr)
+}
+
+func (c *Client) maxDelay() time.Duration {
+	if c.MaxDelay <= 0 {
+		return 30 * time.Second
+	}
+	return c.MaxDelay
+}
+
+// backoff returns the wait before retry number n (starting at 1).
+func (c *Client) backoff(n int) time.Duration {
+	base, max := c.BaseDelay, c.maxDelay()
+	if base <= 0 {
+		base = 500 * time.Millisecond
+	}
+	delay := base << (n - 1)
+	if delay <= 0 || delay > max {
+		delay = max
+	}
+	return delay + time.Duration(mathrand.Int63n(int64(delay)/2+1))
+}
+
+// retryAfter parses the Retry-After header, in seconds or as an HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if d := time.Until(at); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// Breaker is a circuit breaker. After Threshold consecutive failures it
+// opens and rejects calls for Cooldown; then it lets a single trial call
+// through, closing again if it succeeds and reopening if it fails.
+type Breaker struct {
+	Threshold int
+	Cooldown  time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	trial    bool
+}
+
+// NewBreaker returns a closed breaker.
+func NewBreaker(threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{Threshold: threshold, Cooldown: cooldown}
+}
+
+// Allow reports whether a call may go through.
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.openedAt.IsZero() {
+		return true
+	}
+	if b.trial || time.Since(b.openedAt) < b.Cooldown {
+		return false
+	}
+	b.trial = true
+	return true
+}
+
+// Record reports the outcome of an allowed call.
+func (b *Breaker) Record(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if success {
+		b.failures = 0
+		b.openedAt = time.Time{}
+		b.trial = false
+		return
+	}
+	b.failures++
+	if b.trial || b.failures >= b.Threshold {
+		b.openedAt = time.Now()
+		b.trial = false
+	}
+}
+
+// Cancel reports that an allowed call was abandoned by the caller, freeing
+// the trial slot without counting a failure.
+func (b *Breaker) Cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trial = false
+}
+
+// Open reports whether the breaker is rejecting calls.
+func (b *Breaker) Open() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !b.openedAt.IsZero() && (b.trial || time.Since(b.openedAt) < b.Cooldown)
+}
@@ -0,0 +1,230 @@
+package upstream
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// provider answers with the statuses in order, repeating the last one, and
+// keeps the bodies it received.
+type provider struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	statuses []int
+	header   http.Header
+	bodies   []string
+}
+
+func newProvider(t *testing.T, statuses ...int) *provider {
+	p := &provider{statuses: statuses, header: http.Header{}}
+	p.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		p.mu.Lock()
+		status := p.statuses[min(len(p.bodies), len(p.statuses)-1)]
+		p.bodies = append(p.bodies, string(body))
+		for name, values := range p.header {
+			w.Header()[name] = values
+		}
+		p.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(p.Close)
+	return p
+}
+
+func (p *provider) attempts() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.bodies)
+}
+
+func newClient() *Client {
+	return &Client{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 50 * time.Millisecond}
+}
+
+func TestRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		mark     bool
+		statuses []int
+		attempts int
+		status   int
+	}{
+		{"GET until success", "GET", false, []int{503, 502, 200}, 3, 200},
+		{"GET gives up", "GET", false, []int{500}, 3, 500},
+		{"GET client error", "GET", false, []int{404}, 1, 404},
+		{"DELETE", "DELETE", false, []int{504, 204}, 2, 204},
+		{"POST is not retried", "POST", false, []int{503, 200}, 1, 503},
+		{"POST rate limited", "POST", false, []int{429, 201}, 2, 201},
+		{"POST marked idempotent", "POST", true, []int{503, 200}, 2, 200},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newProvider(t, tt.statuses...)
+			var body io.Reader
+			if tt.method == "POST" {
+				body = strings.NewReader(`{"data":{}}`)
+			}
+			req, _ := http.NewRequest(tt.method, p.URL, body)
+			if tt.mark {
+				req = Idempotent(req)
+			}
+			resp, err := newClient().Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.status || p.attempts() != tt.attempts {
+				t.Errorf("got %d after %d attempts, want %d after %d", resp.StatusCode, p.attempts(), tt.status, tt.attempts)
+			}
+			for i, got := range p.bodies {
+				if tt.method == "POST" && got != `{"data":{}}` {
+					t.Errorf("attempt %d sent body %q", i+1, got)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	p := newProvider(t, 429, 200)
+	p.header.Set("Retry-After", "0")
+	req, _ := http.NewRequest("GET", p.URL, nil)
+	resp, err := newClient().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 200 || p.attempts() != 2 {
+		t.Errorf("Retry-After: 0: got %d after %d attempts, want 200 after 2", resp.StatusCode, p.attempts())
+	}
+
+	// A wait longer than MaxDelay is not worth it: the 429 is returned.
+	p = newProvider(t, 429, 200)
+	p.header.Set("Retry-After", "120")
+	req, _ = http.NewRequest("GET", p.URL, nil)
+	start := time.Now()
+	resp, err = newClient().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 429 || p.attempts() != 1 || time.Since(start) > time.Second {
+		t.Errorf("Retry-After: 120: got %d after %d attempts in %v, want 429 at once", resp.StatusCode, p.attempts(), time.Since(start))
+	}
+}
+
+func TestRetryAfterDate(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set("Retry-After", time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))
+	if d, ok := retryAfter(resp); !ok || d != 0 {
+		t.Errorf("past date: got %v, %v, want 0, true", d, ok)
+	}
+	resp.Header.Set("Retry-After", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	if d, ok := retryAfter(resp); !ok || d < 59*time.Minute {
+		t.Errorf("date in an hour: got %v, %v", d, ok)
+	}
+	resp.Header.Set("Retry-After", "soon")
+	if _, ok := retryAfter(resp); ok {
+		t.Error("unparseable Retry-After was accepted")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	c := &Client{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for _, tt := range []struct {
+		n    int
+		base time.Duration
+	}{{1, 100 * time.Millisecond}, {2, 200 * time.Millisecond}, {4, 800 * time.Millisecond}, {5, time.Second}, {70, time.Second}} {
+		for i := 0; i < 20; i++ {
+			// Jitter adds up to half of the delay.
+			if d := c.backoff(tt.n); d < tt.base || d > tt.base+tt.base/2 {
+				t.Fatalf("backoff(%d) = %v, want between %v and %v", tt.n, d, tt.base, tt.base+tt.base/2)
+			}
+		}
+	}
+}
+
+func TestBreaker(t *testing.T) {
+	b := NewBreaker(2, 20*time.Millisecond)
+	b.Record(false)
+	if !b.Allow() || b.Open() {
+		t.Fatal("opened before the threshold")
+	}
+	b.Record(false)
+	if b.Allow() || !b.Open() {
+		t.Fatal("still closed after the threshold")
+	}
+
+	// After the cooldown a single trial goes through; its failure reopens.
+	time.Sleep(25 * time.Millisecond)
+	if !b.Allow() {
+		t.Fatal("no trial after the cooldown")
+	}
+	if b.Allow() {
+		t.Fatal("a second call went through during the trial")
+	}
+	b.Record(false)
+	if b.Allow() {
+		t.Fatal("a failed trial did not reopen the breaker")
+	}
+
+	// An abandoned trial frees the slot without counting.
+	time.Sleep(25 * time.Millisecond)
+	if !b.Allow() {
+		t.Fatal("no trial after the second cooldown")
+	}
+	b.Cancel()
+	if !b.Allow() {
+		t.Fatal("the trial slot was not freed")
+	}
+
+	b.Record(true)
+	if b.Open() || !b.Allow() || !b.Allow() {
+		t.Fatal("a successful trial did not close the breaker")
+	}
+}
+
+func TestClientOpensBreaker(t *testing.T) {
+	p := newProvider(t, 500, 500, 200)
+	c := newClient()
+	c.MaxAttempts = 1
+	c.Breaker = NewBreaker(2, 20*time.Millisecond)
+	var observed []error
+	c.Observe = func(_ *http.Request, _ int, err error, _ time.Duration) { observed = append(observed, err) }
+
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequest("GET", p.URL, nil)
+		resp, err := c.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	req, _ := http.NewRequest("GET", p.URL, nil)
+	if _, err := c.Do(req); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("open breaker: err = %v, want ErrCircuitOpen", err)
+	}
+	if p.attempts() != 2 || len(observed) != 3 || !errors.Is(observed[2], ErrCircuitOpen) {
+		t.Errorf("provider called %d times, observed %v", p.attempts(), observed)
+	}
+
+	time.Sleep(25 * time.Millisecond)
+	req, _ = http.NewRequest("GET", p.URL, nil)
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("trial: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 200 || c.Breaker.Open() {
+		t.Errorf("trial: status %d, breaker open %v", resp.StatusCode, c.Breaker.Open())
+	}
+}
@@ -0,0 +1,329 @@
+// Package watch re-runs flight searches in the background and notifies when
+// the cheapest price of a route drops below a threshold.
+package watch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	mathrand "math/rand"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"go-quickstart/money"
+)
+
+var ErrInvalidWatch = errors.New("watch: invalid watch")
+
+// Watch is a route a customer wants to be alerted about.
+type Watch struct {
+	ID            string    `json:"id" bson:"_id"`
+	Owner         string    `json:"owner" bson:"owner"`
+	Origin        string    `json:"origin" bson:"origin"`
+	Destination   string    `json:"destination" bson:"destination"`
+	DepartureDate string    `json:"departureDate" bson:"departureDate"`
+	Adults        int       `json:"adults" bson:"adults"`
+	Threshold     string    `json:"threshold" bson:"threshold"`
+	Currency      string    `json:"currency" bson:"currency"`
+	NotifyURL     string    `json:"notifyURL,omitempty" bson:"notifyURL,omitempty"`
+	CreatedAt     time.Time `json:"createdAt" bson:"createdAt"`
+	LastCheckedAt time.Time `json:"lastCheckedAt,omitempty" bson:"lastCheckedAt,omitempty"`
+	LastPrice     string    `json:"lastPrice,omitempty" bson:"lastPrice,omitempty"`
+	LastError     string    `json:"lastError,omitempty" bson:"lastError,omitempty"`
+	// Triggered is set while the price stays below the threshold, so the
+	// customer is notified once per crossing instead of on every check.
+	Triggered bool `json:"triggered" bson:"triggered"`
+	// Expired is set once the departure date has passed; the watch is no
+	// longer checked.
+	Expired bool `json:"expired" bson:"expired"`
+}
+
+// departed reports whether the departure date is before today's date at now.
+func (w Watch) departed(now time.Time) bool {
+	return w.DepartureDate < now.UTC().Format("2006-01-02")
+}
+
+// Validate normalizes w and checks that it can be searched.
+func (w *Watch) Validate() error {
+	w.Origin = strings.ToUpper(strings.TrimSpace(w.Origin))
+	w.Destination = strings.ToUpper(strings.TrimSpace(w.Destination))
+	w.Currency = strings.ToUpper(strings.TrimSpace(w.Currency))
+	if len(w.Origin) != 3 || len(w.Destination) != 3 {
+		return fmt.Errorf("%w: origin and destination must be IATA codes", ErrInvalidWatch)
+	}
+	if _, err := time.Parse("2006-01-02", w.DepartureDate); err != nil {
+		return fmt.Errorf("%w: departureDate must be YYYY-MM-DD", ErrInvalidWatch)
+	}
+	if w.departed(time.Now()) {
+		return fmt.Errorf("%w: departureDate has passed", ErrInvalidWatch)
+	}
+	if w.Adults < 1 {
+		return fmt.Errorf("%w: adults must be at least 1", ErrInvalidWatch)
+	}
+	if w.Currency == "" {
+		return fmt.Errorf("%w: currency is required", ErrInvalidWatch)
+	}
+	if _, err := money.Parse(w.Threshold, w.Currency); err != nil {
+		return fmt.Errorf("%w: threshold: %v", ErrInvalidWatch, err)
+	}
+	if w.NotifyURL != "" {
+		if err := checkNotifyURL(w.NotifyURL); err != nil {
+			return fmt.Errorf("%w: notifyURL: %v", ErrInvalidWatch, err)
+		}
+	}
+	return nil
+}
+
+// checkNotifyURL accepts https URLs whose host is a public name or address.
+// Names are checked again when dialing, since they can resolve to anything.
+func checkNotifyURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return errors.New("must be an https URL")
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return errors.New("must not point to this host")
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !isPublic(addr) {
+		return fmt.Errorf("address %s is not public", addr)
+	}
+	return nil
+}
+
+// isPublic reports whether addr can be reached from the internet: not
+// loopback, private, link-local, multicast or unspecified.
+func isPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate()
+}
+
+// NewHTTPClient returns a client for HTTPNotifier that only connects to
+// public addresses over https, whatever the notify URL's name resolves to or
+// redirects to.
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !isPublic(addrPort.Addr()) {
+				return fmt.Errorf("watch: refusing to connect to %s", addrPort.Addr())
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if req.URL.Scheme != "https" {
+				return errors.New("watch: refusing to follow a redirect off https")
+			}
+			if len(via) >= 5 {
+				return errors.New("watch: too many redirects")
+			}
+			return nil
+		},
+	}
+}
+
+// Store persists watches.
+type Store interface {
+	List(ctx context.Context) ([]Watch, error)
+	Update(ctx context.Context, w Watch) error
+}
+
+// SearchFunc returns the cheapest price currently offered for the watch's
+// route, in the watch's currency.
+type SearchFunc func(ctx context.Context, w Watch) (money.Money, error)
+
+// Alert is sent when the cheapest price drops to or below the threshold.
+type Alert struct {
+	Watch Watch       `json:"watch"`
+	Price money.Money `json:"price"`
+	At    time.Time   `json:"at"`
+}
+
+// Notifier delivers alerts.
+type Notifier interface {
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// Notifiers sends every alert to each notifier in turn.
+type Notifiers []Notifier
+
+func (ns Notifiers) Notify(ctx context.Context, alert Alert) error {
+	var errs []error
+	for _, n := range ns {
+		if err := n.Notify(ctx, alert); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// LogNotifier logs alerts.
+type LogNotifier struct{}
+
+func (LogNotifier) Notify(ctx context.Context, alert Alert) error {
+	slog.InfoContext(ctx, "price alert",
+		"watchId", alert.Watch.ID,
+		"route", alert.Watch.Origin+"-"+alert.Watch.Destination,
+		"departureDate", alert.Watch.DepartureDate,
+		"price", alert.Price.String(),
+		"threshold", alert.Watch.Threshold)
+	return nil
+}
+
+// HTTPNotifier posts the alert as JSON to the watch's NotifyURL, if any.
+type HTTPNotifier struct {
+	Client *http.Client
+}
+
+func (n HTTPNotifier) Notify(ctx context.Context, alert Alert) error {
+	if alert.Watch.NotifyURL == "" {
+		return nil
+	}
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, alert.Watch.NotifyURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := n.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("watch: notify %s: %s", alert.Watch.NotifyURL, resp.Status)
+	}
+	return nil
+}
+
+// Scheduler periodically checks every stored watch.
+type Scheduler struct {
+	Store    Store
+	Search   SearchFunc
+	Notifier Notifier
+
+	// Interval between two rounds of checks. Each round is delayed by a
+	// random amount up to Jitter so several servers don't search in lockstep.
+	Interval time.Duration
+	Jitter   time.Duration
+	// RateLimit is the minimum time between two upstream searches.
+	RateLimit time.Duration
+}
+
+// Run checks the watches until ctx is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	for {
+		wait := s.Interval
+		if s.Jitter > 0 {
+			wait += time.Duration(mathrand.Int63n(int64(s.Jitter)))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+		if err := s.CheckAll(ctx); err != nil {
+			slog.ErrorContext(ctx, "checking price watches failed", "error", err)
+		}
+	}
+}
+
+// CheckAll runs one round of checks over all watches.
+func (s *Scheduler) CheckAll(ctx context.Context) error {
+	watches, err := s.Store.List(ctx)
+	if err != nil {
+		return err
+	}
+	var limiter <-chan time.Time
+	if s.RateLimit > 0 {
+		ticker := time.NewTicker(s.RateLimit)
+		defer ticker.Stop()
+		limiter = ticker.C
+	}
+	checked := 0
+	for _, w := range watches {
+		if w.Expired {
+			continue
+		}
+		if checked > 0 && limiter != nil {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-limiter:
+			}
+		}
+		if err := s.Check(ctx, w); err != nil {
+			slog.WarnContext(ctx, "checking price watch failed", "watchId", w.ID, "error", err)
+		}
+		checked++
+	}
+	return nil
+}
+
+// Check searches the watch's route once, stores the result and notifies if
+// the threshold has just been crossed. A watch whose departure date has
+// passed is marked expired instead. Until a notification is delivered the
+// watch is not marked triggered, so the next check tries again.
+func (s *Scheduler) Check(ctx context.Context, w Watch) error {
+	threshold, err := money.Parse(w.Threshold, w.Currency)
+	if err != nil {
+		return err
+	}
+	w.LastCheckedAt = time.Now().UTC()
+	if w.departed(w.LastCheckedAt) {
+		w.Expired = true
+		return s.Store.Update(ctx, w)
+	}
+	price, err := s.Search(ctx, w)
+	if err != nil {
+		w.LastError = err.Error()
+		return errors.Join(err, s.Store.Update(ctx, w))
+	}
+	w.LastError = ""
+	w.LastPrice = price.Amount()
+
+	below, err := price.Cmp(threshold)
+	if err != nil {
+		return err
+	}
+	var notifyErr error
+	switch {
+	case below <= 0 && !w.Triggered:
+		alerted := w
+		alerted.Triggered = true
+		notifyErr = s.Notifier.Notify(ctx, Alert{Watch: alerted, Price: price, At: w.LastCheckedAt})
+		w.Triggered = notifyErr == nil
+	case below > 0:
+		w.Triggered = false
+	}
+	return errors.Join(notifyErr, s.Store.Update(ctx, w))
+}
@@ -0,0 +1,124 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-quickstart/money"
+)
+
+func TestNotifyClientRefusesPrivateAddresses(t *testing.T) {
+	called := false
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	// The URL is checked when the watch is created, but a name can resolve
+	// to a private address later; the client checks every connection.
+	notifier := HTTPNotifier{Client: NewHTTPClient(time.Second)}
+	alert := Alert{Watch: Watch{ID: "w1", NotifyURL: server.URL}}
+	err := notifier.Notify(context.Background(), alert)
+	if err == nil || !strings.Contains(err.Error(), "refusing to connect") {
+		t.Errorf("Notify to %s: err = %v, want a refused connection", server.URL, err)
+	}
+	if called {
+		t.Error("the loopback server was called")
+	}
+}
+
+// memoryStore keeps the last version of each updated watch.
+type memoryStore struct {
+	updated map[string]Watch
+}
+
+func (m *memoryStore) List(context.Context) ([]Watch, error) {
+	var list []Watch
+	for _, w := range m.updated {
+		list = append(list, w)
+	}
+	return list, nil
+}
+
+func (m *memoryStore) Update(_ context.Context, w Watch) error {
+	m.updated[w.ID] = w
+	return nil
+}
+
+type notifierFunc func(context.Context, Alert) error
+
+func (f notifierFunc) Notify(ctx context.Context, alert Alert) error { return f(ctx, alert) }
+
+func TestCheckTriggersOnlyAfterNotifying(t *testing.T) {
+	store := &memoryStore{updated: map[string]Watch{}}
+	var notifyErr error
+	alerts := 0
+	s := &Scheduler{
+		Store: store,
+		Search: func(context.Context, Watch) (money.Money, error) {
+			return money.Parse("90000", "CLP")
+		},
+		Notifier: notifierFunc(func(context.Context, Alert) error {
+			alerts++
+			return notifyErr
+		}),
+	}
+	w := Watch{
+		ID: "w1", Origin: "SCL", Destination: "LIM", Adults: 1, Threshold: "100000", Currency: "CLP",
+		DepartureDate: time.Now().AddDate(0, 1, 0).Format("2006-01-02"),
+	}
+
+	notifyErr = errors.New("receiver down")
+	if err := s.Check(context.Background(), w); !errors.Is(err, notifyErr) {
+		t.Fatalf("Check: err = %v, want the notify error", err)
+	}
+	if store.updated["w1"].Triggered {
+		t.Error("watch triggered although the notification failed")
+	}
+
+	notifyErr = nil
+	if err := s.Check(context.Background(), store.updated["w1"]); err != nil {
+		t.Fatal(err)
+	}
+	if !store.updated["w1"].Triggered {
+		t.Error("watch not triggered after the notification was delivered")
+	}
+	if err := s.Check(context.Background(), store.updated["w1"]); err != nil {
+		t.Fatal(err)
+	}
+	if alerts != 2 {
+		t.Errorf("%d notifications, want 2: one failed and one delivered", alerts)
+	}
+}
+
+func TestCheckAllExpiresDepartedWatches(t *testing.T) {
+	store := &memoryStore{updated: map[string]Watch{
+		"past": {ID: "past", Threshold: "100000", Currency: "CLP", DepartureDate: "2020-01-01"},
+	}}
+	s := &Scheduler{
+		Store: store,
+		Search: func(context.Context, Watch) (money.Money, error) {
+			t.Error("a departed watch was searched")
+			return money.Money{}, errors.New("unexpected search")
+		},
+	}
+	if err := s.CheckAll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !store.updated["past"].Expired {
+		t.Fatal("departed watch not expired")
+	}
+	// Expired watches are skipped from then on.
+	checkedAt := store.updated["past"].LastCheckedAt
+	if err := s.CheckAll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !store.updated["past"].LastCheckedAt.Equal(checkedAt) {
+		t.Error("expired watch checked again")
+	}
+}
@@ -0,0 +1,382 @@
+// Package webhook delivers booking lifecycle events to subscribed URLs.
+//
+// Every delivery is a JSON POST signed with HMAC-SHA256 using the
+// subscription secret. The signature header has the form
+//
+//	X-GoTravel-Signature: t=<unix seconds>,v1=<hex HMAC of "<t>.<body>">
+//
+// so receivers can reject replayed or tampered payloads. Deliveries that fail
+// with a network error or a 5xx response are retried with exponential
+// backoff; other 4xx responses are final. Every attempt is recorded in the
+// store.
+// Retries still waiting when the server shuts down stay pending and are
+// resumed by the next server.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	mathrand "math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"go-quickstart/ids"
+)
+
+// Event types.
+const (
+	EventBookingCreated   = "booking.created"
+	EventBookingCancelled = "booking.cancelled"
+	EventPriceChanged     = "price.changed"
+)
+
+// EventTypes lists every event a subscription may ask for.
+var EventTypes = []string{EventBookingCreated, EventBookingCancelled, EventPriceChanged}
+
+// Delivery statuses.
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusFailed    = "failed"
+)
+
+var (
+	ErrInvalidSubscription = errors.New("webhook: invalid subscription")
+	ErrNotFound            = errors.New("webhook: not found")
+)
+
+// Subscription is an endpoint that wants to receive some event types.
+type Subscription struct {
+	ID        string    `json:"id" bson:"_id"`
+	URL       string    `json:"url" bson:"url"`
+	Secret    string    `json:"secret,omitempty" bson:"secret"`
+	Events    []string  `json:"events" bson:"events"`
+	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+}
+
+// Validate checks the URL and event types. A secret is generated when the
+// subscriber does not provide one.
+func (s *Subscription) Validate() error {
+	u, err := url.Parse(s.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http(s) URL", ErrInvalidSubscription)
+	}
+	if len(s.Events) == 0 {
+		return fmt.Errorf("%w: at least one event type is required", ErrInvalidSubscription)
+	}
+	for _, e := range s.Events {
+		if !validEvent(e) {
+			return fmt.Errorf("%w: unknown event type %q", ErrInvalidSubscription, e)
+		}
+	}
+	if s.Secret == "" {
+		s.Secret = ids.New() + ids.New()
+	}
+	return nil
+}
+
+// Wants reports whether the subscription receives eventType.
+func (s Subscription) Wants(eventType string) bool {
+	for _, e := range s.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is the payload sent to subscribers.
+type Event struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Delivery records the attempts to send one event to one subscription.
+type Delivery struct {
+	ID             string    `json:"id" bson:"_id"`
+	SubscriptionID string    `json:"subscriptionId" bson:"subscriptionId"`
+	EventID        string    `json:"eventId" bson:"eventId"`
+	EventType      string    `json:"eventType" bson:"eventType"`
+	URL            string    `json:"url" bson:"url"`
+	Payload        string    `json:"payload" bson:"payload"`
+	Status         string    `json:"status" bson:"status"`
+	Attempts       int       `json:"attempts" bson:"attempts"`
+	ResponseStatus int       `json:"responseStatus,omitempty" bson:"responseStatus,omitempty"`
+	LastError      string    `json:"lastError,omitempty" bson:"lastError,omitempty"`
+	CreatedAt      time.Time `json:"createdAt" bson:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// Store persists subscriptions and the delivery log.
+type Store interface {
+	InsertSubscription(ctx context.Context, s Subscription) error
+	// DeleteSubscription reports whether the subscription existed.
+	DeleteSubscription(ctx context.Context, id string) (bool, error)
+	Subscriptions(ctx context.Context) ([]Subscription, error)
+	Subscription(ctx context.Context, id string) (Subscription, error)
+	SaveDelivery(ctx context.Context, d Delivery) error
+	Delivery(ctx context.Context, id string) (Delivery, error)
+	// PendingDeliveries returns the deliveries not yet delivered or failed.
+	PendingDeliveries(ctx context.Context) ([]Delivery, error)
+	// Deliveries returns the most recent deliveries, newest first.
+	Deliveries(ctx context.Context, limit int64) ([]Delivery, error)
+}
+
+// Dispatcher publishes events to the subscriptions in Store.
+type Dispatcher struct {
+	Store  Store
+	Client *http.Client
+
+	// MaxAttempts per delivery, including the first one.
+	MaxAttempts int
+	// BaseDelay is the wait before the first retry; it doubles on every
+	// attempt up to MaxDelay, with up to 50% random jitter added.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+
+	once     sync.Once
+	stopping context.Context // done once Shutdown is called
+	stop     context.CancelFunc
+	running  sync.WaitGroup
+}
+
+func (d *Dispatcher) init() {
+	d.once.Do(func() {
+		d.stopping, d.stop = context.WithCancel(context.Background())
+	})
+}
+
+// start delivers in the background, tracked by Shutdown.
+func (d *Dispatcher) start(sub Subscription, delivery Delivery, maxAttempts int) {
+	d.init()
+	d.running.Add(1)
+	go func() {
+		defer d.running.Done()
+		d.deliver(d.stopping, sub, delivery, maxAttempts)
+	}()
+}
+
+// Resume restarts the deliveries left pending by a previous server, with the
+// attempts they had left.
+func (d *Dispatcher) Resume(ctx context.Context) error {
+	pending, err := d.Store.PendingDeliveries(ctx)
+	if err != nil {
+		return err
+	}
+	for _, delivery := range pending {
+		sub, err := d.Store.Subscription(ctx, delivery.SubscriptionID)
+		if errors.Is(err, ErrNotFound) {
+			delivery.Status = StatusFailed
+			delivery.LastError = "subscription deleted"
+			delivery.UpdatedAt = time.Now().UTC()
+			d.save(delivery)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		d.start(sub, delivery, max(d.MaxAttempts-delivery.Attempts, 1))
+	}
+	return nil
+}
+
+// Shutdown stops the retries waiting for their backoff, which stay pending
+// until Resume, and waits for the attempts in flight to finish or for ctx to
+// be done.
+func (d *Dispatcher) Shutdown(ctx context.Context) error {
+	d.init()
+	d.stop()
+	done := make(chan struct{})
+	go func() {
+		d.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Publish sends an event to every subscription interested in eventType.
+// Deliveries run in the background until Shutdown, so Publish only fails if
+// the event cannot be encoded or the subscriptions cannot be read.
+func (d *Dispatcher) Publish(ctx context.Context, eventType string, data any) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	event := Event{ID: ids.New(), Type: eventType, CreatedAt: time.Now().UTC(), Data: raw}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	subscriptions, err := d.Store.Subscriptions(ctx)
+	if err != nil {
+		return err
+	}
+	for _, sub := range subscriptions {
+		if !sub.Wants(eventType) {
+			continue
+		}
+		delivery := Delivery{
+			ID:             ids.New(),
+			SubscriptionID: sub.ID,
+			EventID:        event.ID,
+			EventType:      eventType,
+			URL:            sub.URL,
+			Payload:        string(payload),
+			Status:         StatusPending,
+			CreatedAt:      event.CreatedAt,
+			UpdatedAt:      event.CreatedAt,
+		}
+		if err := d.Store.SaveDelivery(ctx, delivery); err != nil {
+			return err
+		}
+		d.start(sub, delivery, d.MaxAttempts)
+	}
+	return nil
+}
+
+// Replay sends a logged delivery again, once and with a fresh signature. It
+// returns the delivery as recorded after the new attempt.
+func (d *Dispatcher) Replay(ctx context.Context, deliveryID string) (Delivery, error) {
+	delivery, err := d.Store.Delivery(ctx, deliveryID)
+	if err != nil {
+		return Delivery{}, err
+	}
+	sub, err := d.Store.Subscription(ctx, delivery.SubscriptionID)
+	if err != nil {
+		return Delivery{}, err
+	}
+	delivery.Status = StatusPending
+	delivery.LastError = ""
+	return d.deliver(ctx, sub, delivery, 1), nil
+}
+
+// deliver posts the payload until it succeeds or runs out of attempts,
+// saving the delivery after every attempt. When ctx is done before a retry,
+// the delivery is left pending; an attempt already started is not cut short.
+func (d *Dispatcher) deliver(ctx context.Context, sub Subscription, delivery Delivery, maxAttempts int) Delivery {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return delivery
+			case <-time.After(d.backoff(attempt - 1)):
+			}
+		}
+		status, err := d.post(context.WithoutCancel(ctx), sub, delivery)
+		delivery.Attempts++
+		delivery.ResponseStatus = status
+		delivery.UpdatedAt = time.Now().UTC()
+		if err == nil {
+			delivery.Status = StatusDelivered
+			delivery.LastError = ""
+			d.save(delivery)
+			return delivery
+		}
+		delivery.LastError = err.Error()
+		if attempt == maxAttempts || permanent(status) {
+			delivery.Status = StatusFailed
+			d.save(delivery)
+			return delivery
+		}
+		d.save(delivery)
+	}
+	return delivery
+}
+
+// permanent reports whether a receiver answering status rejected the
+// delivery itself, so retrying would not help. Timeouts and rate limits are
+// retried.
+func permanent(status int) bool {
+	return status >= 400 && status < 500 &&
+		status != http.StatusRequestTimeout && status != http.StatusTooManyRequests
+}
+
+func (d *Dispatcher) post(ctx context.Context, sub Subscription, delivery Delivery) (int, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "goTravel-Webhooks/1.0")
+	req.Header.Set("X-GoTravel-Event", delivery.EventType)
+	req.Header.Set("X-GoTravel-Delivery", delivery.ID)
+	req.Header.Set("X-GoTravel-Signature", Sign(sub.Secret, time.Now().Unix(), body))
+
+	client := d.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook: %s responded %s", sub.URL, resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+func (d *Dispatcher) save(delivery Delivery) {
+	if err := d.Store.SaveDelivery(context.Background(), delivery); err != nil {
+		slog.Error("saving webhook delivery failed", "deliveryId", delivery.ID, "error", err)
+	}
+}
+
+// backoff returns the wait before retry number n (starting at 1).
+func (d *Dispatcher) backoff(n int) time.Duration {
+	base, max := d.BaseDelay, d.MaxDelay
+	if base <= 0 {
+		base = time.Second
+	}
+	if max <= 0 {
+		max = 5 * time.Minute
+	}
+	delay := base << (n - 1)
+	if delay <= 0 || delay > max {
+		delay = max
+	}
+	return delay + time.Duration(mathrand.Int63n(int64(delay)/2+1))
+}
+
+// Sign returns the value of the X-GoTravel-Signature header for body.
+func Sign(secret string, timestamp int64, body []byte) string {
+	t := strconv.FormatInt(timestamp, 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(t + "."))
+	mac.Write(body)
+	return "t=" + t + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func validEvent(eventType string) bool {
+	for _, e := range EventTypes {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,268 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryStore keeps one subscription and the deliveries saved for it.
+type memoryStore struct {
+	mu         sync.Mutex
+	sub        Subscription
+	deliveries map[string]Delivery
+}
+
+func newMemoryStore(sub Subscription) *memoryStore {
+	return &memoryStore{sub: sub, deliveries: map[string]Delivery{}}
+}
+
+func (m *memoryStore) InsertSubscription(_ context.Context, sub Subscription) error {
+	m.sub = sub
+	return nil
+}
+
+func (m *memoryStore) DeleteSubscription(_ context.Context, id string) (bool, error) {
+	if id != m.sub.ID {
+		return false, nil
+	}
+	m.sub = Subscription{}
+	return true, nil
+}
+
+func (m *memoryStore) Subscriptions(context.Context) ([]Subscription, error) {
+	return []Subscription{m.sub}, nil
+}
+
+func (m *memoryStore) Subscription(_ context.Context, id string) (Subscription, error) {
+	if id != m.sub.ID {
+		return Subscription{}, ErrNotFound
+	}
+	return m.sub, nil
+}
+
+func (m *memoryStore) SaveDelivery(_ context.Context, d Delivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deliveries[d.ID] = d
+	return nil
+}
+
+func (m *memoryStore) Delivery(_ context.Context, id string) (Delivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	d, ok := m.deliveries[id]
+	if !ok {
+		return Delivery{}, ErrNotFound
+	}
+	return d, nil
+}
+
+func (m *memoryStore) PendingDeliveries(context.Context) ([]Delivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var pending []Delivery
+	for _, d := range m.deliveries {
+		if d.Status == StatusPending {
+			pending = append(pending, d)
+		}
+	}
+	return pending, nil
+}
+
+func (m *memoryStore) Deliveries(_ context.Context, limit int64) ([]Delivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var deliveries []Delivery
+	for _, d := range m.deliveries {
+		deliveries = append(deliveries, d)
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt) })
+	return deliveries[:min(int64(len(deliveries)), limit)], nil
+}
+
+// only returns the single delivery in the store.
+func (m *memoryStore) only(t *testing.T) Delivery {
+	t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.deliveries) != 1 {
+		t.Fatalf("%d deliveries stored, want 1", len(m.deliveries))
+	}
+	for _, d := range m.deliveries {
+		return d
+	}
+	return Delivery{}
+}
+
+// receiver answers with the given statuses in turn, repeating the last one,
+// and checks the signature of every request as a subscriber would.
+type receiver struct {
+	t        *testing.T
+	secret   string
+	statuses []int
+
+	mu       sync.Mutex
+	requests int
+	bodies   []string
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	if !rc.validSignature(r.Header.Get("X-GoTravel-Signature"), body) {
+		rc.t.Errorf("invalid signature %q for %s", r.Header.Get("X-GoTravel-Signature"), body)
+	}
+	rc.mu.Lock()
+	status := rc.statuses[min(rc.requests, len(rc.statuses)-1)]
+	rc.requests++
+	rc.bodies = append(rc.bodies, string(body))
+	rc.mu.Unlock()
+	w.WriteHeader(status)
+}
+
+func (rc *receiver) validSignature(header string, body []byte) bool {
+	var timestamp, signature string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signature = value
+		}
+	}
+	t, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || time.Since(time.Unix(t, 0)) > time.Minute {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(rc.secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	want := hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(signature), []byte(want))
+}
+
+func (rc *receiver) count() int {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.requests
+}
+
+// publish sends one event to a receiver answering statuses and waits until
+// the delivery is delivered or has failed.
+func publish(t *testing.T, statuses ...int) (*receiver, Delivery) {
+	rc := &receiver{t: t, secret: "s3cret", statuses: statuses}
+	server := httptest.NewServer(rc)
+	defer server.Close()
+
+	store := newMemoryStore(Subscription{ID: "sub1", URL: server.URL, Secret: rc.secret, Events: []string{EventBookingCreated}})
+	d := &Dispatcher{Store: store, Client: server.Client(), MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+	if err := d.Publish(context.Background(), EventBookingCreated, map[string]string{"orderId": "o1"}); err != nil {
+		t.Fatal(err)
+	}
+	for deadline := time.Now().Add(5 * time.Second); store.only(t).Status == StatusPending; {
+		if time.Now().After(deadline) {
+			t.Fatal("delivery still pending")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if err := d.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return rc, store.only(t)
+}
+
+func TestDeliverySigned(t *testing.T) {
+	rc, delivery := publish(t, http.StatusNoContent)
+	if delivery.Status != StatusDelivered || delivery.Attempts != 1 {
+		t.Errorf("delivery %s after %d attempts, want delivered after 1", delivery.Status, delivery.Attempts)
+	}
+	var event Event
+	if err := json.Unmarshal([]byte(rc.bodies[0]), &event); err != nil {
+		t.Fatal(err)
+	}
+	if event.Type != EventBookingCreated || string(event.Data) != `{"orderId":"o1"}` {
+		t.Errorf("event %+v, want booking.created for o1", event)
+	}
+}
+
+func TestDeliveryRetriesServerErrors(t *testing.T) {
+	rc, delivery := publish(t, http.StatusInternalServerError, http.StatusServiceUnavailable, http.StatusOK)
+	if rc.count() != 3 {
+		t.Errorf("%d requests, want 3", rc.count())
+	}
+	if delivery.Status != StatusDelivered || delivery.Attempts != 3 {
+		t.Errorf("delivery %s after %d attempts, want delivered after 3", delivery.Status, delivery.Attempts)
+	}
+
+	rc, delivery = publish(t, http.StatusBadGateway)
+	if rc.count() != 3 {
+		t.Errorf("always failing: %d requests, want 3", rc.count())
+	}
+	if delivery.Status != StatusFailed || delivery.ResponseStatus != http.StatusBadGateway {
+		t.Errorf("always failing: delivery %s with %d, want failed with 502", delivery.Status, delivery.ResponseStatus)
+	}
+}
+
+func TestDeliveryDoesNotRetryClientErrors(t *testing.T) {
+	for _, status := range []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusGone} {
+		rc, delivery := publish(t, status, http.StatusOK)
+		if rc.count() != 1 {
+			t.Errorf("%d: %d requests, want 1", status, rc.count())
+		}
+		if delivery.Status != StatusFailed || delivery.Attempts != 1 {
+			t.Errorf("%d: delivery %s after %d attempts, want failed after 1", status, delivery.Status, delivery.Attempts)
+		}
+	}
+	// The receiver asking to slow down is not a rejection.
+	if rc, _ := publish(t, http.StatusTooManyRequests, http.StatusOK); rc.count() != 2 {
+		t.Errorf("429: %d requests, want 2", rc.count())
+	}
+}
+
+func TestShutdownLeavesRetriesPending(t *testing.T) {
+	rc := &receiver{t: t, secret: "s3cret", statuses: []int{http.StatusServiceUnavailable, http.StatusOK}}
+	server := httptest.NewServer(rc)
+	defer server.Close()
+	store := newMemoryStore(Subscription{ID: "sub1", URL: server.URL, Secret: rc.secret, Events: []string{EventBookingCreated}})
+
+	d := &Dispatcher{Store: store, Client: server.Client(), MaxAttempts: 3, BaseDelay: time.Hour, MaxDelay: time.Hour}
+	if err := d.Publish(context.Background(), EventBookingCreated, nil); err != nil {
+		t.Fatal(err)
+	}
+	for deadline := time.Now().Add(5 * time.Second); store.only(t).Attempts == 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("first attempt not made")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if err := d.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if delivery := store.only(t); delivery.Status != StatusPending {
+		t.Fatalf("after shutdown: delivery %s, want pending", delivery.Status)
+	}
+
+	// The next server resumes it with the attempts it has left.
+	next := &Dispatcher{Store: store, Client: server.Client(), MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+	if err := next.Resume(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := next.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if delivery := store.only(t); delivery.Status != StatusDelivered || delivery.Attempts != 2 {
+		t.Errorf("after resume: delivery %s after %d attempts, want delivered after 2", delivery.Status, delivery.Attempts)
+	}
+}